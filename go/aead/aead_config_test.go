@@ -46,4 +46,11 @@ func TestConfigRegistration(t *testing.T) {
 		t.Errorf("unexpected error: %s", err)
 	}
 	var _ = keyManager.(*aead.XChaCha20Poly1305KeyManager)
+
+	// Check for KmsEnvelopeAead key manager.
+	keyManager, err = tink.GetKeyManager(aead.KmsEnvelopeAeadTypeURL)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	var _ = keyManager.(*aead.KmsEnvelopeAeadKeyManager)
 }
@@ -0,0 +1,51 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package aead
+
+import (
+	"github.com/golang/protobuf/proto"
+	gcmpb "github.com/google/tink/proto/aes_gcm_go_proto"
+	tinkpb "github.com/google/tink/proto/tink_go_proto"
+)
+
+// AES128GCMKeyTemplate returns a KeyTemplate for a 16-byte AES-GCM key.
+func AES128GCMKeyTemplate() *tinkpb.KeyTemplate {
+	return aesGCMKeyTemplate(16)
+}
+
+// AES256GCMKeyTemplate returns a KeyTemplate for a 32-byte AES-GCM key.
+func AES256GCMKeyTemplate() *tinkpb.KeyTemplate {
+	return aesGCMKeyTemplate(32)
+}
+
+func aesGCMKeyTemplate(keySize uint32) *tinkpb.KeyTemplate {
+	format := &gcmpb.AesGcmKeyFormat{KeySize: keySize}
+	serializedFormat, _ := proto.Marshal(format)
+	return &tinkpb.KeyTemplate{
+		TypeUrl: AESGCMTypeURL,
+		Value:   serializedFormat,
+	}
+}
+
+// ChaCha20Poly1305KeyTemplate returns a KeyTemplate for a ChaCha20Poly1305 key.
+func ChaCha20Poly1305KeyTemplate() *tinkpb.KeyTemplate {
+	return &tinkpb.KeyTemplate{TypeUrl: ChaCha20Poly1305TypeURL}
+}
+
+// XChaCha20Poly1305KeyTemplate returns a KeyTemplate for an
+// XChaCha20Poly1305 key.
+func XChaCha20Poly1305KeyTemplate() *tinkpb.KeyTemplate {
+	return &tinkpb.KeyTemplate{TypeUrl: XChaCha20Poly1305TypeURL}
+}
@@ -0,0 +1,112 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package aead
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	subtleAEAD "github.com/google/tink/go/subtle/aead"
+	"github.com/google/tink/go/subtle/random"
+	gcmpb "github.com/google/tink/proto/aes_gcm_go_proto"
+	tinkpb "github.com/google/tink/proto/tink_go_proto"
+)
+
+const (
+	// AESGCMTypeURL is the type URL of keys that support AES-GCM, registered
+	// in the global registry.
+	AESGCMTypeURL    = "type.googleapis.com/google.crypto.tink.AesGcmKey"
+	aesGCMKeyVersion = 0
+)
+
+// AESGCMKeyManager generates AES-GCM keys and produces instances of AESGCM.
+type AESGCMKeyManager struct{}
+
+func newAESGCMKeyManager() *AESGCMKeyManager {
+	return new(AESGCMKeyManager)
+}
+
+// Primitive constructs an AESGCM for the given serialized AesGcmKey.
+func (km *AESGCMKeyManager) Primitive(serializedKey []byte) (interface{}, error) {
+	if len(serializedKey) == 0 {
+		return nil, errors.New("aes_gcm_key_manager: invalid key")
+	}
+	key := new(gcmpb.AesGcmKey)
+	if err := proto.Unmarshal(serializedKey, key); err != nil {
+		return nil, err
+	}
+	if err := km.validateKey(key); err != nil {
+		return nil, err
+	}
+	return subtleAEAD.NewAESGCM(key.KeyValue)
+}
+
+// NewKey generates a new AesGcmKey from the given serialized AesGcmKeyFormat.
+func (km *AESGCMKeyManager) NewKey(serializedKeyFormat []byte) (proto.Message, error) {
+	if len(serializedKeyFormat) == 0 {
+		return nil, errors.New("aes_gcm_key_manager: invalid key format")
+	}
+	keyFormat := new(gcmpb.AesGcmKeyFormat)
+	if err := proto.Unmarshal(serializedKeyFormat, keyFormat); err != nil {
+		return nil, err
+	}
+	if err := validateAESKeySize(keyFormat.KeySize); err != nil {
+		return nil, err
+	}
+	return &gcmpb.AesGcmKey{
+		Version:  aesGCMKeyVersion,
+		KeyValue: random.GetRandomBytes(keyFormat.KeySize),
+	}, nil
+}
+
+// NewKeyData generates a new KeyData wrapping a freshly generated AesGcmKey.
+// It is used primarily by tink.NewKeyData.
+func (km *AESGCMKeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
+	key, err := km.NewKey(serializedKeyFormat)
+	if err != nil {
+		return nil, err
+	}
+	serializedKey, err := proto.Marshal(key)
+	if err != nil {
+		return nil, err
+	}
+	return &tinkpb.KeyData{
+		TypeUrl:         AESGCMTypeURL,
+		Value:           serializedKey,
+		KeyMaterialType: tinkpb.KeyData_SYMMETRIC,
+	}, nil
+}
+
+// DoesSupport returns true iff this key manager supports key type typeURL.
+func (km *AESGCMKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == AESGCMTypeURL
+}
+
+// TypeURL returns the type URL that identifies the AES-GCM key type.
+func (km *AESGCMKeyManager) TypeURL() string {
+	return AESGCMTypeURL
+}
+
+func (km *AESGCMKeyManager) validateKey(key *gcmpb.AesGcmKey) error {
+	return validateAESKeySize(uint32(len(key.KeyValue)))
+}
+
+func validateAESKeySize(keySize uint32) error {
+	if keySize != 16 && keySize != 32 {
+		return fmt.Errorf("aes_gcm_key_manager: invalid AES key size: %d", keySize)
+	}
+	return nil
+}
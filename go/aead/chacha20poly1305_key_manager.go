@@ -0,0 +1,94 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package aead
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	subtleAEAD "github.com/google/tink/go/subtle/aead"
+	"github.com/google/tink/go/subtle/random"
+	chachapb "github.com/google/tink/proto/chacha20_poly1305_go_proto"
+	tinkpb "github.com/google/tink/proto/tink_go_proto"
+)
+
+const (
+	// ChaCha20Poly1305TypeURL is the type URL of keys that support
+	// ChaCha20-Poly1305, registered in the global registry.
+	ChaCha20Poly1305TypeURL    = "type.googleapis.com/google.crypto.tink.ChaCha20Poly1305Key"
+	chaCha20Poly1305KeyVersion = 0
+	chaCha20Poly1305KeySize    = 32
+)
+
+// ChaCha20Poly1305KeyManager generates ChaCha20Poly1305 keys and produces
+// instances of ChaCha20Poly1305.
+type ChaCha20Poly1305KeyManager struct{}
+
+func newChaCha20Poly1305KeyManager() *ChaCha20Poly1305KeyManager {
+	return new(ChaCha20Poly1305KeyManager)
+}
+
+// Primitive constructs a ChaCha20Poly1305 for the given serialized key.
+func (km *ChaCha20Poly1305KeyManager) Primitive(serializedKey []byte) (interface{}, error) {
+	if len(serializedKey) == 0 {
+		return nil, errors.New("chacha20poly1305_key_manager: invalid key")
+	}
+	key := new(chachapb.ChaCha20Poly1305Key)
+	if err := proto.Unmarshal(serializedKey, key); err != nil {
+		return nil, err
+	}
+	if len(key.KeyValue) != chaCha20Poly1305KeySize {
+		return nil, fmt.Errorf("chacha20poly1305_key_manager: invalid key size: %d", len(key.KeyValue))
+	}
+	return subtleAEAD.NewChaCha20Poly1305(key.KeyValue)
+}
+
+// NewKey generates a new ChaCha20Poly1305Key. ChaCha20Poly1305 has no key
+// format fields, so serializedKeyFormat is ignored.
+func (km *ChaCha20Poly1305KeyManager) NewKey(serializedKeyFormat []byte) (proto.Message, error) {
+	return &chachapb.ChaCha20Poly1305Key{
+		Version:  chaCha20Poly1305KeyVersion,
+		KeyValue: random.GetRandomBytes(chaCha20Poly1305KeySize),
+	}, nil
+}
+
+// NewKeyData generates a new KeyData wrapping a freshly generated
+// ChaCha20Poly1305Key. It is used primarily by tink.NewKeyData.
+func (km *ChaCha20Poly1305KeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
+	key, err := km.NewKey(serializedKeyFormat)
+	if err != nil {
+		return nil, err
+	}
+	serializedKey, err := proto.Marshal(key)
+	if err != nil {
+		return nil, err
+	}
+	return &tinkpb.KeyData{
+		TypeUrl:         ChaCha20Poly1305TypeURL,
+		Value:           serializedKey,
+		KeyMaterialType: tinkpb.KeyData_SYMMETRIC,
+	}, nil
+}
+
+// DoesSupport returns true iff this key manager supports key type typeURL.
+func (km *ChaCha20Poly1305KeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == ChaCha20Poly1305TypeURL
+}
+
+// TypeURL returns the type URL that identifies the ChaCha20Poly1305 key type.
+func (km *ChaCha20Poly1305KeyManager) TypeURL() string {
+	return ChaCha20Poly1305TypeURL
+}
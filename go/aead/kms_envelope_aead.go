@@ -0,0 +1,121 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package aead
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/tink"
+	tinkpb "github.com/google/tink/proto/tink_go_proto"
+)
+
+// kmsEnvelopeAEADLenPrefixSize is the number of bytes used to hold the
+// length of the wrapped DEK that is prefixed onto every ciphertext produced
+// by kmsEnvelopeAEAD.
+const kmsEnvelopeAEADLenPrefixSize = 4
+
+// kmsEnvelopeAEAD is an AEAD that implements envelope encryption: for every
+// Encrypt call it generates a fresh DEK from dekTemplate, uses it to encrypt
+// the plaintext locally, and wraps the DEK with remote (the KEK). The
+// ciphertext format is len(wrappedDEK) || wrappedDEK || dekCiphertext, so
+// that Decrypt can recover the wrapped DEK, unwrap it remotely, and use it to
+// decrypt the remainder. The DEK never leaves the process in cleartext.
+type kmsEnvelopeAEAD struct {
+	dekTemplate *tinkpb.KeyTemplate
+	remote      tink.AEAD
+}
+
+// newKMSEnvelopeAEAD creates a new kmsEnvelopeAEAD.
+func newKMSEnvelopeAEAD(dekTemplate *tinkpb.KeyTemplate, remote tink.AEAD) *kmsEnvelopeAEAD {
+	return &kmsEnvelopeAEAD{
+		dekTemplate: dekTemplate,
+		remote:      remote,
+	}
+}
+
+// Encrypt implements the tink.AEAD interface.
+func (a *kmsEnvelopeAEAD) Encrypt(plaintext, associatedData []byte) ([]byte, error) {
+	dekAEAD, serializedDEK, err := a.newDEK()
+	if err != nil {
+		return nil, err
+	}
+	payload, err := dekAEAD.Encrypt(plaintext, associatedData)
+	if err != nil {
+		return nil, err
+	}
+	wrappedDEK, err := a.remote.Encrypt(serializedDEK, []byte{})
+	if err != nil {
+		return nil, fmt.Errorf("kms_envelope_aead: failed to wrap DEK: %s", err)
+	}
+	lenPrefix := make([]byte, kmsEnvelopeAEADLenPrefixSize)
+	binary.BigEndian.PutUint32(lenPrefix, uint32(len(wrappedDEK)))
+	ciphertext := make([]byte, 0, len(lenPrefix)+len(wrappedDEK)+len(payload))
+	ciphertext = append(ciphertext, lenPrefix...)
+	ciphertext = append(ciphertext, wrappedDEK...)
+	ciphertext = append(ciphertext, payload...)
+	return ciphertext, nil
+}
+
+// Decrypt implements the tink.AEAD interface.
+func (a *kmsEnvelopeAEAD) Decrypt(ciphertext, associatedData []byte) ([]byte, error) {
+	if len(ciphertext) < kmsEnvelopeAEADLenPrefixSize {
+		return nil, errors.New("kms_envelope_aead: ciphertext too short")
+	}
+	dekLen := binary.BigEndian.Uint32(ciphertext[:kmsEnvelopeAEADLenPrefixSize])
+	rest := ciphertext[kmsEnvelopeAEADLenPrefixSize:]
+	if uint32(len(rest)) < dekLen {
+		return nil, errors.New("kms_envelope_aead: invalid wrapped DEK length")
+	}
+	wrappedDEK, payload := rest[:dekLen], rest[dekLen:]
+	serializedDEK, err := a.remote.Decrypt(wrappedDEK, []byte{})
+	if err != nil {
+		return nil, fmt.Errorf("kms_envelope_aead: failed to unwrap DEK: %s", err)
+	}
+	dek, err := tink.Primitive(a.dekTemplate.TypeUrl, serializedDEK)
+	if err != nil {
+		return nil, err
+	}
+	dekAEAD, ok := dek.(tink.AEAD)
+	if !ok {
+		return nil, errors.New("kms_envelope_aead: dek_template does not produce an AEAD primitive")
+	}
+	return dekAEAD.Decrypt(payload, associatedData)
+}
+
+// newDEK generates a fresh DEK from a.dekTemplate and returns both the AEAD
+// primitive instantiated from it and its serialized key, which is what gets
+// wrapped by the remote KEK.
+func (a *kmsEnvelopeAEAD) newDEK() (tink.AEAD, []byte, error) {
+	key, err := tink.NewKey(a.dekTemplate)
+	if err != nil {
+		return nil, nil, fmt.Errorf("kms_envelope_aead: failed to generate DEK: %s", err)
+	}
+	serializedKey, err := proto.Marshal(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	p, err := tink.Primitive(a.dekTemplate.TypeUrl, serializedKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	dekAEAD, ok := p.(tink.AEAD)
+	if !ok {
+		return nil, nil, errors.New("kms_envelope_aead: dek_template does not produce an AEAD primitive")
+	}
+	return dekAEAD, serializedKey, nil
+}
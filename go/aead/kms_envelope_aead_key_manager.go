@@ -0,0 +1,128 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package aead
+
+import (
+	"errors"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/tink"
+	kmsenvelopepb "github.com/google/tink/proto/kms_envelope_go_proto"
+	tinkpb "github.com/google/tink/proto/tink_go_proto"
+)
+
+const (
+	// KmsEnvelopeAeadTypeURL is the type URL of keys that support KMS
+	// envelope AEAD encryption, registered in the global registry.
+	KmsEnvelopeAeadTypeURL    = "type.googleapis.com/google.crypto.tink.KmsEnvelopeAeadKey"
+	kmsEnvelopeAeadKeyVersion = 0
+)
+
+// KmsEnvelopeAeadKeyManager generates KmsEnvelopeAeadKey keys and produces
+// kmsEnvelopeAEAD primitives that encrypt with a DEK generated per-message
+// and wrapped with a remote KEK resolved via tink.GetKmsClient. The key
+// itself carries no secret material -- only a KEK URI and the DEK template --
+// so its KeyData is marked REMOTE.
+type KmsEnvelopeAeadKeyManager struct{}
+
+// NewKmsEnvelopeAeadKeyManager creates a new KmsEnvelopeAeadKeyManager.
+func NewKmsEnvelopeAeadKeyManager() *KmsEnvelopeAeadKeyManager {
+	return new(KmsEnvelopeAeadKeyManager)
+}
+
+// Primitive constructs a kmsEnvelopeAEAD for the given serialized
+// KmsEnvelopeAeadKey, resolving its KEK URI to a registered tink.KmsClient.
+func (km *KmsEnvelopeAeadKeyManager) Primitive(serializedKey []byte) (interface{}, error) {
+	if len(serializedKey) == 0 {
+		return nil, errors.New("kms_envelope_aead_key_manager: invalid key")
+	}
+	key := new(kmsenvelopepb.KmsEnvelopeAeadKey)
+	if err := proto.Unmarshal(serializedKey, key); err != nil {
+		return nil, err
+	}
+	if err := validateKmsEnvelopeAeadKeyFormat(key.Params); err != nil {
+		return nil, err
+	}
+	client, err := tink.GetKmsClient(key.Params.KekUri)
+	if err != nil {
+		return nil, err
+	}
+	remote, err := client.GetAEAD(key.Params.KekUri)
+	if err != nil {
+		return nil, err
+	}
+	return newKMSEnvelopeAEAD(key.Params.DekTemplate, remote), nil
+}
+
+// NewKey generates a new KmsEnvelopeAeadKey from the given serialized
+// KmsEnvelopeAeadKeyFormat. The key carries no secret material of its own.
+func (km *KmsEnvelopeAeadKeyManager) NewKey(serializedKeyFormat []byte) (proto.Message, error) {
+	if len(serializedKeyFormat) == 0 {
+		return nil, errors.New("kms_envelope_aead_key_manager: invalid key format")
+	}
+	keyFormat := new(kmsenvelopepb.KmsEnvelopeAeadKeyFormat)
+	if err := proto.Unmarshal(serializedKeyFormat, keyFormat); err != nil {
+		return nil, err
+	}
+	if err := validateKmsEnvelopeAeadKeyFormat(keyFormat); err != nil {
+		return nil, err
+	}
+	return &kmsenvelopepb.KmsEnvelopeAeadKey{
+		Version: kmsEnvelopeAeadKeyVersion,
+		Params:  keyFormat,
+	}, nil
+}
+
+// NewKeyData generates a new KeyData wrapping a freshly generated
+// KmsEnvelopeAeadKey. It is used primarily by tink.NewKeyData. The
+// resulting KeyData is marked REMOTE since the key holds no local secret.
+func (km *KmsEnvelopeAeadKeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
+	key, err := km.NewKey(serializedKeyFormat)
+	if err != nil {
+		return nil, err
+	}
+	serializedKey, err := proto.Marshal(key)
+	if err != nil {
+		return nil, err
+	}
+	return &tinkpb.KeyData{
+		TypeUrl:         KmsEnvelopeAeadTypeURL,
+		Value:           serializedKey,
+		KeyMaterialType: tinkpb.KeyData_REMOTE,
+	}, nil
+}
+
+// DoesSupport returns true iff this key manager supports key type typeURL.
+func (km *KmsEnvelopeAeadKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == KmsEnvelopeAeadTypeURL
+}
+
+// TypeURL returns the type URL that identifies the KMS envelope AEAD key type.
+func (km *KmsEnvelopeAeadKeyManager) TypeURL() string {
+	return KmsEnvelopeAeadTypeURL
+}
+
+func validateKmsEnvelopeAeadKeyFormat(format *kmsenvelopepb.KmsEnvelopeAeadKeyFormat) error {
+	if format == nil {
+		return errors.New("kms_envelope_aead_key_manager: missing params")
+	}
+	if format.KekUri == "" {
+		return errors.New("kms_envelope_aead_key_manager: missing kek_uri")
+	}
+	if format.DekTemplate == nil {
+		return errors.New("kms_envelope_aead_key_manager: missing dek_template")
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package aead_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/tink/go/aead"
+	"github.com/google/tink/go/tink"
+)
+
+// fakeKmsClient is a minimal in-memory tink.KmsClient used to test
+// KmsEnvelopeAeadKeyManager without talking to a real KMS. It "wraps" a key
+// by prefixing it with keyURI, and "unwraps" by stripping that prefix.
+type fakeKmsClient struct {
+	keyURIPrefix string
+}
+
+func (c *fakeKmsClient) Supported(keyURI string) bool {
+	return strings.HasPrefix(keyURI, c.keyURIPrefix)
+}
+
+func (c *fakeKmsClient) GetAEAD(keyURI string) (tink.AEAD, error) {
+	return &fakeKekAEAD{keyURI: keyURI}, nil
+}
+
+type fakeKekAEAD struct {
+	keyURI string
+}
+
+func (a *fakeKekAEAD) Encrypt(plaintext, associatedData []byte) ([]byte, error) {
+	return append([]byte(a.keyURI+"|"), plaintext...), nil
+}
+
+func (a *fakeKekAEAD) Decrypt(ciphertext, associatedData []byte) ([]byte, error) {
+	prefix := []byte(a.keyURI + "|")
+	if !bytes.HasPrefix(ciphertext, prefix) {
+		return nil, errors.New("fake_kek_aead: decryption failed")
+	}
+	return ciphertext[len(prefix):], nil
+}
+
+func TestKmsEnvelopeAeadEncryptDecrypt(t *testing.T) {
+	if err := aead.Register(); err != nil {
+		t.Fatalf("aead.Register() err = %v, want nil", err)
+	}
+	tink.RegisterKmsClient(&fakeKmsClient{keyURIPrefix: "fake-kms://"})
+
+	template := aead.CreateKmsEnvelopeAEADKeyTemplate("fake-kms://key1", aead.AES128GCMKeyTemplate())
+	keyData, err := tink.NewKeyData(template)
+	if err != nil {
+		t.Fatalf("tink.NewKeyData() err = %v, want nil", err)
+	}
+
+	p, err := tink.PrimitiveFromKeyData(keyData)
+	if err != nil {
+		t.Fatalf("tink.PrimitiveFromKeyData() err = %v, want nil", err)
+	}
+	a, ok := p.(tink.AEAD)
+	if !ok {
+		t.Fatalf("primitive does not implement tink.AEAD")
+	}
+
+	plaintext := []byte("kms envelope plaintext")
+	associatedData := []byte("associated data")
+	ciphertext, err := a.Encrypt(plaintext, associatedData)
+	if err != nil {
+		t.Fatalf("Encrypt() err = %v, want nil", err)
+	}
+	got, err := a.Decrypt(ciphertext, associatedData)
+	if err != nil {
+		t.Fatalf("Decrypt() err = %v, want nil", err)
+	}
+	if !bytes.Equal(got, plaintext) {
+		t.Errorf("Decrypt() = %q, want %q", got, plaintext)
+	}
+
+	// Wrong associated data must fail to decrypt.
+	if _, err := a.Decrypt(ciphertext, []byte("wrong")); err == nil {
+		t.Errorf("Decrypt() with wrong associated data succeeded, want error")
+	}
+}
+
+func TestKmsEnvelopeAeadKeyManagerUnsupportedKmsURI(t *testing.T) {
+	if err := aead.Register(); err != nil {
+		t.Fatalf("aead.Register() err = %v, want nil", err)
+	}
+	template := aead.CreateKmsEnvelopeAEADKeyTemplate("unregistered-kms://key1", aead.AES128GCMKeyTemplate())
+	keyData, err := tink.NewKeyData(template)
+	if err != nil {
+		t.Fatalf("tink.NewKeyData() err = %v, want nil", err)
+	}
+	if _, err := tink.PrimitiveFromKeyData(keyData); err == nil {
+		t.Errorf("PrimitiveFromKeyData() succeeded for an unregistered KMS URI, want error")
+	}
+}
+
+func TestKmsEnvelopeAeadKeyManagerMissingDekTemplate(t *testing.T) {
+	if err := aead.Register(); err != nil {
+		t.Fatalf("aead.Register() err = %v, want nil", err)
+	}
+	keyManager, err := tink.GetKeyManager(aead.KmsEnvelopeAeadTypeURL)
+	if err != nil {
+		t.Fatalf("tink.GetKeyManager() err = %v, want nil", err)
+	}
+	template := aead.CreateKmsEnvelopeAEADKeyTemplate("fake-kms://key1", nil)
+	if _, err := keyManager.NewKey(template.Value); err == nil {
+		t.Errorf("NewKey() with missing dek_template succeeded, want error")
+	}
+}
@@ -0,0 +1,39 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package aead
+
+import (
+	"github.com/golang/protobuf/proto"
+	kmsenvelopepb "github.com/google/tink/proto/kms_envelope_go_proto"
+	tinkpb "github.com/google/tink/proto/tink_go_proto"
+)
+
+// CreateKmsEnvelopeAEADKeyTemplate creates a KeyTemplate for a
+// KmsEnvelopeAeadKey that wraps DEKs generated from dekTemplate with the
+// remote key at kekURI, e.g. "aws-kms://arn:aws:kms:..." or
+// "gcp-kms://projects/.../cryptoKeys/...". kekURI must be resolvable by a
+// tink.KmsClient registered via tink.RegisterKmsClient before the resulting
+// template's primitive is instantiated.
+func CreateKmsEnvelopeAEADKeyTemplate(kekURI string, dekTemplate *tinkpb.KeyTemplate) *tinkpb.KeyTemplate {
+	format := &kmsenvelopepb.KmsEnvelopeAeadKeyFormat{
+		KekUri:      kekURI,
+		DekTemplate: dekTemplate,
+	}
+	serializedFormat, _ := proto.Marshal(format)
+	return &tinkpb.KeyTemplate{
+		TypeUrl: KmsEnvelopeAeadTypeURL,
+		Value:   serializedFormat,
+	}
+}
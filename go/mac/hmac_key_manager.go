@@ -0,0 +1,145 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package mac
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	subtleMac "github.com/google/tink/go/subtle/mac"
+	"github.com/google/tink/go/subtle/random"
+	commonpb "github.com/google/tink/proto/common_go_proto"
+	hmacpb "github.com/google/tink/proto/hmac_go_proto"
+	tinkpb "github.com/google/tink/proto/tink_go_proto"
+)
+
+const (
+	// HMACTypeURL is the type URL of keys that support HMAC, registered in
+	// the global registry.
+	HMACTypeURL           = "type.googleapis.com/google.crypto.tink.HmacKey"
+	hmacKeyVersion        = 0
+	minHMACKeySizeInBytes = 16
+)
+
+// HMACKeyManager generates HMAC keys and produces instances of HMAC.
+type HMACKeyManager struct{}
+
+// NewHMACKeyManager creates a new HMACKeyManager.
+func NewHMACKeyManager() *HMACKeyManager {
+	return new(HMACKeyManager)
+}
+
+// Primitive constructs a HMAC for the given serialized HmacKey.
+func (km *HMACKeyManager) Primitive(serializedKey []byte) (interface{}, error) {
+	if len(serializedKey) == 0 {
+		return nil, errors.New("hmac_key_manager: invalid key")
+	}
+	key := new(hmacpb.HmacKey)
+	if err := proto.Unmarshal(serializedKey, key); err != nil {
+		return nil, err
+	}
+	if err := km.validateKey(key); err != nil {
+		return nil, err
+	}
+	hash := key.Params.Hash.String()
+	return subtleMac.NewHMAC(hash, key.KeyValue, key.Params.TagSize)
+}
+
+// NewKey generates a new HmacKey from the given serialized HmacKeyFormat.
+func (km *HMACKeyManager) NewKey(serializedKeyFormat []byte) (proto.Message, error) {
+	if len(serializedKeyFormat) == 0 {
+		return nil, errors.New("hmac_key_manager: invalid key format")
+	}
+	keyFormat := new(hmacpb.HmacKeyFormat)
+	if err := proto.Unmarshal(serializedKeyFormat, keyFormat); err != nil {
+		return nil, err
+	}
+	if err := km.validateKeyFormat(keyFormat); err != nil {
+		return nil, err
+	}
+	return &hmacpb.HmacKey{
+		Version:  hmacKeyVersion,
+		Params:   keyFormat.Params,
+		KeyValue: random.GetRandomBytes(keyFormat.KeySize),
+	}, nil
+}
+
+// NewKeyData generates a new KeyData wrapping a freshly generated HmacKey.
+// It is used primarily by tink.NewKeyData.
+func (km *HMACKeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
+	key, err := km.NewKey(serializedKeyFormat)
+	if err != nil {
+		return nil, err
+	}
+	serializedKey, err := proto.Marshal(key)
+	if err != nil {
+		return nil, err
+	}
+	return &tinkpb.KeyData{
+		TypeUrl:         HMACTypeURL,
+		Value:           serializedKey,
+		KeyMaterialType: tinkpb.KeyData_SYMMETRIC,
+	}, nil
+}
+
+// DoesSupport returns true iff this key manager supports key type typeURL.
+func (km *HMACKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == HMACTypeURL
+}
+
+// TypeURL returns the type URL that identifies the HMAC key type.
+func (km *HMACKeyManager) TypeURL() string {
+	return HMACTypeURL
+}
+
+func (km *HMACKeyManager) validateKey(key *hmacpb.HmacKey) error {
+	if key.Params == nil {
+		return errors.New("hmac_key_manager: missing params")
+	}
+	if len(key.KeyValue) < minHMACKeySizeInBytes {
+		return fmt.Errorf("hmac_key_manager: key too short: %d bytes", len(key.KeyValue))
+	}
+	return validateHMACParams(key.Params)
+}
+
+func (km *HMACKeyManager) validateKeyFormat(format *hmacpb.HmacKeyFormat) error {
+	if format.KeySize < minHMACKeySizeInBytes {
+		return fmt.Errorf("hmac_key_manager: key size too small: %d bytes", format.KeySize)
+	}
+	return validateHMACParams(format.Params)
+}
+
+func validateHMACParams(params *hmacpb.HmacParams) error {
+	if params == nil {
+		return errors.New("hmac_key_manager: missing params")
+	}
+	var digestSize uint32
+	switch params.Hash {
+	case commonpb.HashType_SHA256:
+		digestSize = 32
+	case commonpb.HashType_SHA512:
+		digestSize = 64
+	default:
+		return fmt.Errorf("hmac_key_manager: unsupported hash: %s", params.Hash)
+	}
+	if params.TagSize == 0 {
+		return errors.New("hmac_key_manager: invalid tag size")
+	}
+	if params.TagSize > digestSize {
+		return fmt.Errorf("hmac_key_manager: tag size %d too big for hash %s, want <= %d", params.TagSize, params.Hash, digestSize)
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package mac_test
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/mac"
+	"github.com/google/tink/go/tink"
+	commonpb "github.com/google/tink/proto/common_go_proto"
+	hmacpb "github.com/google/tink/proto/hmac_go_proto"
+)
+
+func TestHMACKeyManagerDoesSupport(t *testing.T) {
+	km := mac.NewHMACKeyManager()
+	if !km.DoesSupport(mac.HMACTypeURL) {
+		t.Errorf("DoesSupport(%s) = false, want true", mac.HMACTypeURL)
+	}
+	if km.DoesSupport("not.the.right.url") {
+		t.Errorf("DoesSupport() = true for an unrelated type URL, want false")
+	}
+}
+
+func TestHMACKeyManagerNewKeyAndPrimitive(t *testing.T) {
+	km := mac.NewHMACKeyManager()
+	format := &hmacpb.HmacKeyFormat{
+		KeySize: 32,
+		Params: &hmacpb.HmacParams{
+			Hash:    commonpb.HashType_SHA256,
+			TagSize: 16,
+		},
+	}
+	serializedFormat, err := proto.Marshal(format)
+	if err != nil {
+		t.Fatalf("proto.Marshal() err = %v, want nil", err)
+	}
+	key, err := km.NewKey(serializedFormat)
+	if err != nil {
+		t.Fatalf("NewKey() err = %v, want nil", err)
+	}
+	serializedKey, err := proto.Marshal(key)
+	if err != nil {
+		t.Fatalf("proto.Marshal() err = %v, want nil", err)
+	}
+	p, err := km.Primitive(serializedKey)
+	if err != nil {
+		t.Fatalf("Primitive() err = %v, want nil", err)
+	}
+	m, ok := p.(tink.MAC)
+	if !ok {
+		t.Fatalf("Primitive() does not implement tink.MAC")
+	}
+	data := []byte("data to authenticate")
+	tag, err := m.ComputeMAC(data)
+	if err != nil {
+		t.Fatalf("ComputeMAC() err = %v, want nil", err)
+	}
+	if len(tag) != int(format.Params.TagSize) {
+		t.Errorf("len(tag) = %d, want %d", len(tag), format.Params.TagSize)
+	}
+	if err := m.VerifyMAC(tag, data); err != nil {
+		t.Errorf("VerifyMAC() err = %v, want nil", err)
+	}
+	if err := m.VerifyMAC(tag, []byte("different data")); err == nil {
+		t.Errorf("VerifyMAC() succeeded for mismatched data, want error")
+	}
+}
+
+func TestHMACKeyManagerRejectsTagSizeTooBigForHash(t *testing.T) {
+	km := mac.NewHMACKeyManager()
+	tests := []struct {
+		name    string
+		hash    commonpb.HashType
+		tagSize uint32
+	}{
+		{"SHA256 tag too big", commonpb.HashType_SHA256, 33},
+		{"SHA256 tag way too big", commonpb.HashType_SHA256, 1000},
+		{"SHA512 tag too big", commonpb.HashType_SHA512, 65},
+	}
+	for _, tt := range tests {
+		format := &hmacpb.HmacKeyFormat{
+			KeySize: 64,
+			Params: &hmacpb.HmacParams{
+				Hash:    tt.hash,
+				TagSize: tt.tagSize,
+			},
+		}
+		serializedFormat, err := proto.Marshal(format)
+		if err != nil {
+			t.Fatalf("%s: proto.Marshal() err = %v, want nil", tt.name, err)
+		}
+		if _, err := km.NewKey(serializedFormat); err == nil {
+			t.Errorf("%s: NewKey() succeeded, want error", tt.name)
+		}
+
+		key := &hmacpb.HmacKey{
+			Version:  0,
+			Params:   format.Params,
+			KeyValue: make([]byte, 64),
+		}
+		serializedKey, err := proto.Marshal(key)
+		if err != nil {
+			t.Fatalf("%s: proto.Marshal() err = %v, want nil", tt.name, err)
+		}
+		if _, err := km.Primitive(serializedKey); err == nil {
+			t.Errorf("%s: Primitive() succeeded, want error", tt.name)
+		}
+	}
+}
+
+func TestHMACKeyManagerRejectsUnsupportedHash(t *testing.T) {
+	km := mac.NewHMACKeyManager()
+	format := &hmacpb.HmacKeyFormat{
+		KeySize: 32,
+		Params: &hmacpb.HmacParams{
+			Hash:    commonpb.HashType_UNKNOWN_HASH,
+			TagSize: 16,
+		},
+	}
+	serializedFormat, err := proto.Marshal(format)
+	if err != nil {
+		t.Fatalf("proto.Marshal() err = %v, want nil", err)
+	}
+	if _, err := km.NewKey(serializedFormat); err == nil {
+		t.Errorf("NewKey() succeeded for an unsupported hash, want error")
+	}
+}
+
+func TestHMACKeyManagerRejectsShortKey(t *testing.T) {
+	km := mac.NewHMACKeyManager()
+	key := &hmacpb.HmacKey{
+		Version: 0,
+		Params: &hmacpb.HmacParams{
+			Hash:    commonpb.HashType_SHA256,
+			TagSize: 16,
+		},
+		KeyValue: make([]byte, 8),
+	}
+	serializedKey, err := proto.Marshal(key)
+	if err != nil {
+		t.Fatalf("proto.Marshal() err = %v, want nil", err)
+	}
+	if _, err := km.Primitive(serializedKey); err == nil {
+		t.Errorf("Primitive() succeeded for a too-short key, want error")
+	}
+}
@@ -0,0 +1,55 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package mac
+
+import (
+	"github.com/golang/protobuf/proto"
+	commonpb "github.com/google/tink/proto/common_go_proto"
+	hmacpb "github.com/google/tink/proto/hmac_go_proto"
+	tinkpb "github.com/google/tink/proto/tink_go_proto"
+)
+
+// HMACSHA256Tag128KeyTemplate returns a KeyTemplate for a 32-byte HMAC-SHA256
+// key producing 16-byte tags.
+func HMACSHA256Tag128KeyTemplate() *tinkpb.KeyTemplate {
+	return hmacKeyTemplate(32, commonpb.HashType_SHA256, 16)
+}
+
+// HMACSHA256Tag256KeyTemplate returns a KeyTemplate for a 32-byte HMAC-SHA256
+// key producing 32-byte (full) tags.
+func HMACSHA256Tag256KeyTemplate() *tinkpb.KeyTemplate {
+	return hmacKeyTemplate(32, commonpb.HashType_SHA256, 32)
+}
+
+// HMACSHA512Tag512KeyTemplate returns a KeyTemplate for a 64-byte HMAC-SHA512
+// key producing 64-byte (full) tags.
+func HMACSHA512Tag512KeyTemplate() *tinkpb.KeyTemplate {
+	return hmacKeyTemplate(64, commonpb.HashType_SHA512, 64)
+}
+
+func hmacKeyTemplate(keySize uint32, hash commonpb.HashType, tagSize uint32) *tinkpb.KeyTemplate {
+	format := &hmacpb.HmacKeyFormat{
+		KeySize: keySize,
+		Params: &hmacpb.HmacParams{
+			Hash:    hash,
+			TagSize: tagSize,
+		},
+	}
+	serializedFormat, _ := proto.Marshal(format)
+	return &tinkpb.KeyTemplate{
+		TypeUrl: HMACTypeURL,
+		Value:   serializedFormat,
+	}
+}
@@ -0,0 +1,153 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package streamingaead
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/subtle/random"
+	subtleStreamingAEAD "github.com/google/tink/go/subtle/streamingaead"
+	gcmhkdfpb "github.com/google/tink/proto/aes_gcm_hkdf_streaming_go_proto"
+	commonpb "github.com/google/tink/proto/common_go_proto"
+	tinkpb "github.com/google/tink/proto/tink_go_proto"
+)
+
+const (
+	// AESGCMHKDFStreamingTypeURL is the type URL of keys that support
+	// AES-GCM-HKDF streaming AEAD, registered in the global registry.
+	AESGCMHKDFStreamingTypeURL    = "type.googleapis.com/google.crypto.tink.AesGcmHkdfStreamingKey"
+	aesGCMHKDFStreamingKeyVersion = 0
+
+	// firstSegmentOffset is 0 because, unlike the keyset-aware streaming
+	// AEAD wrappers in other Tink language implementations, the primitive
+	// returned here is used directly and does not have a keyset
+	// output-prefix written ahead of it on the wire.
+	firstSegmentOffset = 0
+)
+
+// AESGCMHKDFStreamingKeyManager generates AesGcmHkdfStreamingKey keys and
+// produces subtleStreamingAEAD.AESGCMHKDFStreaming primitives, which
+// implement tink.StreamingAEAD.
+type AESGCMHKDFStreamingKeyManager struct{}
+
+func newAESGCMHKDFStreamingKeyManager() *AESGCMHKDFStreamingKeyManager {
+	return new(AESGCMHKDFStreamingKeyManager)
+}
+
+// Primitive constructs an AESGCMHKDFStreaming for the given serialized
+// AesGcmHkdfStreamingKey.
+func (km *AESGCMHKDFStreamingKeyManager) Primitive(serializedKey []byte) (interface{}, error) {
+	if len(serializedKey) == 0 {
+		return nil, errors.New("aes_gcm_hkdf_streaming_key_manager: invalid key")
+	}
+	key := new(gcmhkdfpb.AesGcmHkdfStreamingKey)
+	if err := proto.Unmarshal(serializedKey, key); err != nil {
+		return nil, err
+	}
+	if err := km.validateKey(key); err != nil {
+		return nil, err
+	}
+	params := key.Params
+	return subtleStreamingAEAD.NewAESGCMHKDFStreaming(
+		key.KeyValue,
+		params.HkdfHashType.String(),
+		int(params.DerivedKeySize),
+		int(params.CiphertextSegmentSize),
+		firstSegmentOffset,
+	)
+}
+
+// NewKey generates a new AesGcmHkdfStreamingKey from the given serialized
+// AesGcmHkdfStreamingKeyFormat.
+func (km *AESGCMHKDFStreamingKeyManager) NewKey(serializedKeyFormat []byte) (proto.Message, error) {
+	if len(serializedKeyFormat) == 0 {
+		return nil, errors.New("aes_gcm_hkdf_streaming_key_manager: invalid key format")
+	}
+	keyFormat := new(gcmhkdfpb.AesGcmHkdfStreamingKeyFormat)
+	if err := proto.Unmarshal(serializedKeyFormat, keyFormat); err != nil {
+		return nil, err
+	}
+	if err := km.validateKeyFormat(keyFormat); err != nil {
+		return nil, err
+	}
+	return &gcmhkdfpb.AesGcmHkdfStreamingKey{
+		Version:  aesGCMHKDFStreamingKeyVersion,
+		KeyValue: random.GetRandomBytes(keyFormat.KeySize),
+		Params:   keyFormat.Params,
+	}, nil
+}
+
+// NewKeyData generates a new KeyData wrapping a freshly generated
+// AesGcmHkdfStreamingKey. It is used primarily by tink.NewKeyData.
+func (km *AESGCMHKDFStreamingKeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
+	key, err := km.NewKey(serializedKeyFormat)
+	if err != nil {
+		return nil, err
+	}
+	serializedKey, err := proto.Marshal(key)
+	if err != nil {
+		return nil, err
+	}
+	return &tinkpb.KeyData{
+		TypeUrl:         AESGCMHKDFStreamingTypeURL,
+		Value:           serializedKey,
+		KeyMaterialType: tinkpb.KeyData_SYMMETRIC,
+	}, nil
+}
+
+// DoesSupport returns true iff this key manager supports key type typeURL.
+func (km *AESGCMHKDFStreamingKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == AESGCMHKDFStreamingTypeURL
+}
+
+// TypeURL returns the type URL that identifies the AES-GCM-HKDF streaming
+// key type.
+func (km *AESGCMHKDFStreamingKeyManager) TypeURL() string {
+	return AESGCMHKDFStreamingTypeURL
+}
+
+func (km *AESGCMHKDFStreamingKeyManager) validateKey(key *gcmhkdfpb.AesGcmHkdfStreamingKey) error {
+	if len(key.KeyValue) < 16 {
+		return fmt.Errorf("aes_gcm_hkdf_streaming_key_manager: key too short: %d bytes", len(key.KeyValue))
+	}
+	return km.validateParams(key.Params)
+}
+
+func (km *AESGCMHKDFStreamingKeyManager) validateKeyFormat(format *gcmhkdfpb.AesGcmHkdfStreamingKeyFormat) error {
+	if format.KeySize < 16 {
+		return fmt.Errorf("aes_gcm_hkdf_streaming_key_manager: key size too small: %d bytes", format.KeySize)
+	}
+	return km.validateParams(format.Params)
+}
+
+func (km *AESGCMHKDFStreamingKeyManager) validateParams(params *gcmhkdfpb.AesGcmHkdfStreamingParams) error {
+	if params == nil {
+		return errors.New("aes_gcm_hkdf_streaming_key_manager: missing params")
+	}
+	if params.DerivedKeySize != 16 && params.DerivedKeySize != 32 {
+		return fmt.Errorf("aes_gcm_hkdf_streaming_key_manager: invalid derived key size: %d", params.DerivedKeySize)
+	}
+	switch params.HkdfHashType {
+	case commonpb.HashType_SHA256, commonpb.HashType_SHA512:
+	default:
+		return fmt.Errorf("aes_gcm_hkdf_streaming_key_manager: unsupported HKDF hash type: %s", params.HkdfHashType)
+	}
+	if params.CiphertextSegmentSize < 1+params.DerivedKeySize+24 {
+		return errors.New("aes_gcm_hkdf_streaming_key_manager: ciphertext segment size too small")
+	}
+	return nil
+}
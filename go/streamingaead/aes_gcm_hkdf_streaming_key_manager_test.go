@@ -0,0 +1,126 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package streamingaead_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/google/tink/go/streamingaead"
+	"github.com/google/tink/go/tink"
+)
+
+func TestAESGCMHKDFStreamingEncryptDecrypt(t *testing.T) {
+	if err := streamingaead.Register(); err != nil {
+		t.Fatalf("streamingaead.Register() err = %v, want nil", err)
+	}
+	template := streamingaead.AES128GCMHKDF4KBKeyTemplate()
+	keyData, err := tink.NewKeyData(template)
+	if err != nil {
+		t.Fatalf("tink.NewKeyData() err = %v, want nil", err)
+	}
+	p, err := tink.PrimitiveFromKeyData(keyData)
+	if err != nil {
+		t.Fatalf("tink.PrimitiveFromKeyData() err = %v, want nil", err)
+	}
+	streamingAEAD, ok := p.(tink.StreamingAEAD)
+	if !ok {
+		t.Fatalf("primitive does not implement tink.StreamingAEAD")
+	}
+
+	associatedData := []byte("associated data")
+	// Plaintext spans several 4KB segments, including a final partial one.
+	plaintext := bytes.Repeat([]byte("tink streaming aead "), 1000)
+
+	var ciphertext bytes.Buffer
+	w, err := streamingAEAD.NewEncryptingWriter(&ciphertext, associatedData)
+	if err != nil {
+		t.Fatalf("NewEncryptingWriter() err = %v, want nil", err)
+	}
+	if _, err := w.Write(plaintext); err != nil {
+		t.Fatalf("Write() err = %v, want nil", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() err = %v, want nil", err)
+	}
+
+	r, err := streamingAEAD.NewDecryptingReader(bytes.NewReader(ciphertext.Bytes()), associatedData)
+	if err != nil {
+		t.Fatalf("NewDecryptingReader() err = %v, want nil", err)
+	}
+	got, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatalf("ReadAll() err = %v, want nil", err)
+	}
+	if !bytes.Equal(got, plaintext) {
+		t.Errorf("decrypted plaintext does not match original")
+	}
+
+	// Wrong associated data must fail to decrypt.
+	r2, err := streamingAEAD.NewDecryptingReader(bytes.NewReader(ciphertext.Bytes()), []byte("wrong"))
+	if err != nil {
+		t.Fatalf("NewDecryptingReader() err = %v, want nil", err)
+	}
+	if _, err := ioutil.ReadAll(r2); err == nil {
+		t.Errorf("ReadAll() with wrong associated data succeeded, want error")
+	}
+
+	// Truncated ciphertext must fail to decrypt to the original plaintext.
+	truncated := ciphertext.Bytes()[:len(ciphertext.Bytes())-1]
+	r3, err := streamingAEAD.NewDecryptingReader(bytes.NewReader(truncated), associatedData)
+	if err != nil {
+		t.Fatalf("NewDecryptingReader() err = %v, want nil", err)
+	}
+	if got, err := ioutil.ReadAll(r3); err == nil && bytes.Equal(got, plaintext) {
+		t.Errorf("ReadAll() of truncated ciphertext returned original plaintext, want error or mismatch")
+	}
+}
+
+func TestAESGCMHKDFStreamingEmptyPlaintext(t *testing.T) {
+	if err := streamingaead.Register(); err != nil {
+		t.Fatalf("streamingaead.Register() err = %v, want nil", err)
+	}
+	keyData, err := tink.NewKeyData(streamingaead.AES256GCMHKDF4KBKeyTemplate())
+	if err != nil {
+		t.Fatalf("tink.NewKeyData() err = %v, want nil", err)
+	}
+	p, err := tink.PrimitiveFromKeyData(keyData)
+	if err != nil {
+		t.Fatalf("tink.PrimitiveFromKeyData() err = %v, want nil", err)
+	}
+	streamingAEAD := p.(tink.StreamingAEAD)
+
+	var ciphertext bytes.Buffer
+	w, err := streamingAEAD.NewEncryptingWriter(&ciphertext, nil)
+	if err != nil {
+		t.Fatalf("NewEncryptingWriter() err = %v, want nil", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() err = %v, want nil", err)
+	}
+
+	r, err := streamingAEAD.NewDecryptingReader(bytes.NewReader(ciphertext.Bytes()), nil)
+	if err != nil {
+		t.Fatalf("NewDecryptingReader() err = %v, want nil", err)
+	}
+	got, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatalf("ReadAll() err = %v, want nil", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("got %d bytes of plaintext, want 0", len(got))
+	}
+}
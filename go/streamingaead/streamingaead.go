@@ -0,0 +1,27 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+// Package streamingaead provides implementations of the tink.StreamingAEAD
+// interface: authenticated encryption with associated data for data streams
+// too large to hold in memory at once.
+package streamingaead
+
+import "github.com/google/tink/go/tink"
+
+// Register registers the standard streaming AEAD key managers with the
+// tink registry, making them accessible via tink.GetKeyManager,
+// tink.NewKeyData, etc.
+func Register() error {
+	return tink.RegisterKeyManager(newAESGCMHKDFStreamingKeyManager())
+}
@@ -0,0 +1,35 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package streamingaead_test
+
+import (
+	"testing"
+
+	"github.com/google/tink/go/streamingaead"
+	"github.com/google/tink/go/tink"
+)
+
+func TestConfigRegistration(t *testing.T) {
+	err := streamingaead.Register()
+	if err != nil {
+		t.Errorf("cannot register standard key types")
+	}
+	// Check for AES-GCM-HKDF streaming key manager.
+	keyManager, err := tink.GetKeyManager(streamingaead.AESGCMHKDFStreamingTypeURL)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	var _ = keyManager.(*streamingaead.AESGCMHKDFStreamingKeyManager)
+}
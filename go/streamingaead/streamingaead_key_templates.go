@@ -0,0 +1,54 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package streamingaead
+
+import (
+	"github.com/golang/protobuf/proto"
+	gcmhkdfpb "github.com/google/tink/proto/aes_gcm_hkdf_streaming_go_proto"
+	commonpb "github.com/google/tink/proto/common_go_proto"
+	tinkpb "github.com/google/tink/proto/tink_go_proto"
+)
+
+// defaultCiphertextSegmentSize is 4KB, matching the other Tink language
+// implementations' default for AES-GCM-HKDF streaming keys.
+const defaultCiphertextSegmentSize = 4096
+
+// AES128GCMHKDF4KBKeyTemplate returns a KeyTemplate for a 16-byte
+// AES-GCM-HKDF streaming key with 4KB ciphertext segments.
+func AES128GCMHKDF4KBKeyTemplate() *tinkpb.KeyTemplate {
+	return aesGCMHKDFStreamingKeyTemplate(16, defaultCiphertextSegmentSize)
+}
+
+// AES256GCMHKDF4KBKeyTemplate returns a KeyTemplate for a 32-byte
+// AES-GCM-HKDF streaming key with 4KB ciphertext segments.
+func AES256GCMHKDF4KBKeyTemplate() *tinkpb.KeyTemplate {
+	return aesGCMHKDFStreamingKeyTemplate(32, defaultCiphertextSegmentSize)
+}
+
+func aesGCMHKDFStreamingKeyTemplate(keySize, ciphertextSegmentSize uint32) *tinkpb.KeyTemplate {
+	format := &gcmhkdfpb.AesGcmHkdfStreamingKeyFormat{
+		KeySize: keySize,
+		Params: &gcmhkdfpb.AesGcmHkdfStreamingParams{
+			CiphertextSegmentSize: ciphertextSegmentSize,
+			DerivedKeySize:        keySize,
+			HkdfHashType:          commonpb.HashType_SHA256,
+		},
+	}
+	serializedFormat, _ := proto.Marshal(format)
+	return &tinkpb.KeyTemplate{
+		TypeUrl: AESGCMHKDFStreamingTypeURL,
+		Value:   serializedFormat,
+	}
+}
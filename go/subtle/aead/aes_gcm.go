@@ -0,0 +1,87 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+// Package aead provides subtle implementations of the tink.AEAD interface.
+package aead
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"errors"
+	"fmt"
+
+	"github.com/google/tink/go/subtle/random"
+)
+
+const (
+	// AESGCMIVSize is the only IV size that this implementation supports.
+	AESGCMIVSize = 12
+	// AESGCMTagSize is the only tag size that this implementation supports.
+	AESGCMTagSize = 16
+)
+
+// AESGCM is an implementation of the tink.AEAD interface.
+type AESGCM struct {
+	Key []byte
+}
+
+// NewAESGCM returns an AESGCM instance wrapping key, which must be 16 or 32
+// bytes long.
+func NewAESGCM(key []byte) (*AESGCM, error) {
+	keySize := len(key)
+	if keySize != 16 && keySize != 32 {
+		return nil, fmt.Errorf("aes_gcm: invalid AES key size: %d", keySize)
+	}
+	return &AESGCM{Key: key}, nil
+}
+
+// Encrypt encrypts plaintext with associatedData as additional authenticated
+// data. The resulting ciphertext consists of a randomly generated
+// nonce followed by the GCM ciphertext and tag.
+func (a *AESGCM) Encrypt(plaintext, associatedData []byte) ([]byte, error) {
+	aead, err := a.newCipher()
+	if err != nil {
+		return nil, err
+	}
+	nonce := random.GetRandomBytes(AESGCMIVSize)
+	ciphertext := aead.Seal(nonce, nonce, plaintext, associatedData)
+	return ciphertext, nil
+}
+
+// Decrypt decrypts ciphertext with associatedData as additional
+// authenticated data.
+func (a *AESGCM) Decrypt(ciphertext, associatedData []byte) ([]byte, error) {
+	if len(ciphertext) < AESGCMIVSize {
+		return nil, errors.New("aes_gcm: ciphertext too short")
+	}
+	aead, err := a.newCipher()
+	if err != nil {
+		return nil, err
+	}
+	nonce := ciphertext[:AESGCMIVSize]
+	payload := ciphertext[AESGCMIVSize:]
+	plaintext, err := aead.Open(nil, nonce, payload, associatedData)
+	if err != nil {
+		return nil, fmt.Errorf("aes_gcm: decryption failed: %s", err)
+	}
+	return plaintext, nil
+}
+
+func (a *AESGCM) newCipher() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(a.Key)
+	if err != nil {
+		return nil, fmt.Errorf("aes_gcm: failed to create block cipher: %s", err)
+	}
+	return cipher.NewGCMWithTagSize(block, AESGCMTagSize)
+}
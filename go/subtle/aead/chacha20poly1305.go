@@ -0,0 +1,103 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package aead
+
+import (
+	"fmt"
+
+	"github.com/google/tink/go/subtle/random"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// ChaCha20Poly1305 is an implementation of the tink.AEAD interface.
+type ChaCha20Poly1305 struct {
+	Key []byte
+}
+
+// NewChaCha20Poly1305 returns a ChaCha20Poly1305 instance wrapping key, which
+// must be 32 bytes long.
+func NewChaCha20Poly1305(key []byte) (*ChaCha20Poly1305, error) {
+	if len(key) != chacha20poly1305.KeySize {
+		return nil, fmt.Errorf("chacha20poly1305: invalid key size: %d", len(key))
+	}
+	return &ChaCha20Poly1305{Key: key}, nil
+}
+
+// Encrypt encrypts plaintext with associatedData as additional authenticated
+// data, prefixing the result with a randomly generated nonce.
+func (c *ChaCha20Poly1305) Encrypt(plaintext, associatedData []byte) ([]byte, error) {
+	aead, err := chacha20poly1305.New(c.Key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := random.GetRandomBytes(uint32(aead.NonceSize()))
+	return aead.Seal(nonce, nonce, plaintext, associatedData), nil
+}
+
+// Decrypt decrypts ciphertext with associatedData as additional
+// authenticated data.
+func (c *ChaCha20Poly1305) Decrypt(ciphertext, associatedData []byte) ([]byte, error) {
+	aead, err := chacha20poly1305.New(c.Key)
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, fmt.Errorf("chacha20poly1305: ciphertext too short")
+	}
+	nonce := ciphertext[:aead.NonceSize()]
+	payload := ciphertext[aead.NonceSize():]
+	return aead.Open(nil, nonce, payload, associatedData)
+}
+
+// XChaCha20Poly1305 is an implementation of the tink.AEAD interface using
+// the longer, randomly-safe XChaCha20-Poly1305 nonce.
+type XChaCha20Poly1305 struct {
+	Key []byte
+}
+
+// NewXChaCha20Poly1305 returns an XChaCha20Poly1305 instance wrapping key,
+// which must be 32 bytes long.
+func NewXChaCha20Poly1305(key []byte) (*XChaCha20Poly1305, error) {
+	if len(key) != chacha20poly1305.KeySize {
+		return nil, fmt.Errorf("xchacha20poly1305: invalid key size: %d", len(key))
+	}
+	return &XChaCha20Poly1305{Key: key}, nil
+}
+
+// Encrypt encrypts plaintext with associatedData as additional authenticated
+// data, prefixing the result with a randomly generated nonce.
+func (x *XChaCha20Poly1305) Encrypt(plaintext, associatedData []byte) ([]byte, error) {
+	aead, err := chacha20poly1305.NewX(x.Key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := random.GetRandomBytes(uint32(aead.NonceSize()))
+	return aead.Seal(nonce, nonce, plaintext, associatedData), nil
+}
+
+// Decrypt decrypts ciphertext with associatedData as additional
+// authenticated data.
+func (x *XChaCha20Poly1305) Decrypt(ciphertext, associatedData []byte) ([]byte, error) {
+	aead, err := chacha20poly1305.NewX(x.Key)
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, fmt.Errorf("xchacha20poly1305: ciphertext too short")
+	}
+	nonce := ciphertext[:aead.NonceSize()]
+	payload := ciphertext[aead.NonceSize():]
+	return aead.Open(nil, nonce, payload, associatedData)
+}
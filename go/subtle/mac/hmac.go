@@ -0,0 +1,72 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+// Package mac provides subtle implementations of the tink.MAC interface.
+package mac
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"hash"
+)
+
+// HMAC is an implementation of the tink.MAC interface.
+type HMAC struct {
+	HashFunc func() hash.Hash
+	Key      []byte
+	TagSize  uint32
+}
+
+// NewHMAC returns an HMAC instance using hashAlg ("SHA256" or "SHA512"),
+// key, and a tag of tagSize bytes.
+func NewHMAC(hashAlg string, key []byte, tagSize uint32) (*HMAC, error) {
+	var hashFunc func() hash.Hash
+	switch hashAlg {
+	case "SHA256":
+		hashFunc = sha256.New
+	case "SHA512":
+		hashFunc = sha512.New
+	default:
+		return nil, fmt.Errorf("hmac: unsupported hash algorithm: %s", hashAlg)
+	}
+	if tagSize == 0 {
+		return nil, errors.New("hmac: invalid tag size")
+	}
+	return &HMAC{HashFunc: hashFunc, Key: key, TagSize: tagSize}, nil
+}
+
+// ComputeMAC computes the message authentication code (MAC) for data.
+func (h *HMAC) ComputeMAC(data []byte) ([]byte, error) {
+	mac := hmac.New(h.HashFunc, h.Key)
+	if _, err := mac.Write(data); err != nil {
+		return nil, err
+	}
+	tag := mac.Sum(nil)
+	return tag[:h.TagSize], nil
+}
+
+// VerifyMAC verifies that mac is a correct authentication code for data.
+func (h *HMAC) VerifyMAC(mac, data []byte) error {
+	expected, err := h.ComputeMAC(data)
+	if err != nil {
+		return err
+	}
+	if !hmac.Equal(expected, mac) {
+		return errors.New("hmac: invalid MAC")
+	}
+	return nil
+}
@@ -0,0 +1,363 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+// Package streamingaead provides subtle implementations of the
+// tink.StreamingAEAD interface.
+package streamingaead
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+
+	"golang.org/x/crypto/hkdf"
+
+	"github.com/google/tink/go/subtle/random"
+)
+
+const (
+	noncePrefixSizeInBytes    = 7
+	nonceSizeInBytes          = 12
+	tagSizeInBytes            = 16
+	segmentCounterSizeInBytes = 4
+	headerLenFieldSizeInBytes = 1
+)
+
+// AESGCMHKDFStreaming is a segmented, streaming implementation of the
+// tink.StreamingAEAD interface for data too large to hold in memory at
+// once. The ciphertext is a header (a random salt and nonce prefix) followed
+// by a sequence of independently-decryptable segments, each AES-GCM
+// encrypted with a per-ciphertext key derived from mainKey via HKDF and a
+// per-segment nonce built from the nonce prefix, a 32-bit big-endian segment
+// counter, and a flag byte marking the final segment.
+type AESGCMHKDFStreaming struct {
+	mainKey            []byte
+	hkdfHashFunc       func() hash.Hash
+	keySizeInBytes     int
+	segmentSizeInBytes int
+	firstSegmentOffset int
+}
+
+// NewAESGCMHKDFStreaming creates an AESGCMHKDFStreaming primitive.
+//
+// mainKey is the secret HKDF is applied to and must be at least
+// keySizeInBytes long; keySizeInBytes is the size (16 or 32) of the keys
+// HKDF derives from it for each ciphertext. hkdfHashAlg ("SHA256" or "SHA512") selects the hash function
+// HKDF uses to derive those per-ciphertext keys. segmentSizeInBytes is the
+// size of each ciphertext segment on the wire; it must leave room for at
+// least the GCM tag plus one byte of plaintext in the first segment.
+// firstSegmentOffset reserves extra bytes out of the first segment's
+// plaintext budget for a caller-written prefix (e.g. a keyset
+// output-prefix) that precedes the stream produced here.
+func NewAESGCMHKDFStreaming(mainKey []byte, hkdfHashAlg string, keySizeInBytes, segmentSizeInBytes, firstSegmentOffset int) (*AESGCMHKDFStreaming, error) {
+	if keySizeInBytes != 16 && keySizeInBytes != 32 {
+		return nil, fmt.Errorf("aes_gcm_hkdf_streaming: invalid key size: %d", keySizeInBytes)
+	}
+	if len(mainKey) < keySizeInBytes {
+		return nil, errors.New("aes_gcm_hkdf_streaming: mainKey too short")
+	}
+	if segmentSizeInBytes <= tagSizeInBytes+firstSegmentOffset {
+		return nil, errors.New("aes_gcm_hkdf_streaming: segmentSizeInBytes too small")
+	}
+	var hkdfHashFunc func() hash.Hash
+	switch hkdfHashAlg {
+	case "SHA256":
+		hkdfHashFunc = sha256.New
+	case "SHA512":
+		hkdfHashFunc = sha512.New
+	default:
+		return nil, fmt.Errorf("aes_gcm_hkdf_streaming: unsupported HKDF hash algorithm: %s", hkdfHashAlg)
+	}
+	return &AESGCMHKDFStreaming{
+		mainKey:            mainKey,
+		hkdfHashFunc:       hkdfHashFunc,
+		keySizeInBytes:     keySizeInBytes,
+		segmentSizeInBytes: segmentSizeInBytes,
+		firstSegmentOffset: firstSegmentOffset,
+	}, nil
+}
+
+func headerSize(keySizeInBytes int) int {
+	return headerLenFieldSizeInBytes + keySizeInBytes + noncePrefixSizeInBytes
+}
+
+// NewEncryptingWriter returns an io.WriteCloser that encrypts everything
+// written to it segment by segment, writing a freshly generated header
+// followed by the ciphertext segments to w. Close must be called to flush
+// the final, possibly partial, segment.
+func (a *AESGCMHKDFStreaming) NewEncryptingWriter(w io.Writer, associatedData []byte) (io.WriteCloser, error) {
+	salt := random.GetRandomBytes(uint32(a.keySizeInBytes))
+	noncePrefix := random.GetRandomBytes(noncePrefixSizeInBytes)
+	derivedKey, err := a.deriveKey(salt, associatedData)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newSegmentAEAD(derivedKey)
+	if err != nil {
+		return nil, err
+	}
+	header := make([]byte, 0, headerSize(a.keySizeInBytes))
+	header = append(header, byte(headerSize(a.keySizeInBytes)))
+	header = append(header, salt...)
+	header = append(header, noncePrefix...)
+	if _, err := w.Write(header); err != nil {
+		return nil, fmt.Errorf("aes_gcm_hkdf_streaming: failed to write header: %s", err)
+	}
+	plaintextSegmentSize := a.segmentSizeInBytes - tagSizeInBytes
+	return &encryptWriter{
+		w:                    w,
+		aead:                 aead,
+		noncePrefix:          noncePrefix,
+		plaintextSegmentSize: plaintextSegmentSize,
+		firstSegmentOffset:   a.firstSegmentOffset,
+		buf:                  make([]byte, 0, plaintextSegmentSize),
+	}, nil
+}
+
+// NewDecryptingReader returns an io.Reader that reads the header and
+// ciphertext segments written by NewEncryptingWriter from r and returns the
+// decrypted plaintext.
+func (a *AESGCMHKDFStreaming) NewDecryptingReader(r io.Reader, associatedData []byte) (io.Reader, error) {
+	headerLenByte := make([]byte, headerLenFieldSizeInBytes)
+	if _, err := io.ReadFull(r, headerLenByte); err != nil {
+		return nil, fmt.Errorf("aes_gcm_hkdf_streaming: failed to read header length: %s", err)
+	}
+	wantLen := headerSize(a.keySizeInBytes)
+	if int(headerLenByte[0]) != wantLen {
+		return nil, errors.New("aes_gcm_hkdf_streaming: unexpected header length")
+	}
+	rest := make([]byte, wantLen-headerLenFieldSizeInBytes)
+	if _, err := io.ReadFull(r, rest); err != nil {
+		return nil, fmt.Errorf("aes_gcm_hkdf_streaming: failed to read header: %s", err)
+	}
+	salt, noncePrefix := rest[:a.keySizeInBytes], rest[a.keySizeInBytes:]
+	derivedKey, err := a.deriveKey(salt, associatedData)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newSegmentAEAD(derivedKey)
+	if err != nil {
+		return nil, err
+	}
+	return &decryptReader{
+		r:                     r,
+		aead:                  aead,
+		noncePrefix:           noncePrefix,
+		ciphertextSegmentSize: a.segmentSizeInBytes,
+		firstSegmentOffset:    a.firstSegmentOffset,
+	}, nil
+}
+
+func (a *AESGCMHKDFStreaming) deriveKey(salt, associatedData []byte) ([]byte, error) {
+	kdf := hkdf.New(a.hkdfHashFunc, a.mainKey, salt, associatedData)
+	key := make([]byte, a.keySizeInBytes)
+	if _, err := io.ReadFull(kdf, key); err != nil {
+		return nil, fmt.Errorf("aes_gcm_hkdf_streaming: failed to derive key: %s", err)
+	}
+	return key, nil
+}
+
+func newSegmentAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// segmentNonce builds the nonce for segment segmentNr: noncePrefix, a
+// 32-bit big-endian segment counter, and a flag byte that is 1 for the
+// final segment of the stream and 0 otherwise.
+func segmentNonce(noncePrefix []byte, segmentNr uint32, last bool) []byte {
+	nonce := make([]byte, 0, nonceSizeInBytes)
+	nonce = append(nonce, noncePrefix...)
+	counter := make([]byte, segmentCounterSizeInBytes)
+	binary.BigEndian.PutUint32(counter, segmentNr)
+	nonce = append(nonce, counter...)
+	if last {
+		nonce = append(nonce, 1)
+	} else {
+		nonce = append(nonce, 0)
+	}
+	return nonce
+}
+
+// encryptWriter buffers plaintext until a full segment is ready, seals it
+// with AES-GCM under a per-segment nonce, and writes the ciphertext to w.
+type encryptWriter struct {
+	w                    io.Writer
+	aead                 cipher.AEAD
+	noncePrefix          []byte
+	plaintextSegmentSize int
+	firstSegmentOffset   int
+	segmentNr            uint32
+	buf                  []byte
+	closed               bool
+}
+
+func (ew *encryptWriter) capacity() int {
+	if ew.segmentNr == 0 {
+		return ew.plaintextSegmentSize - ew.firstSegmentOffset
+	}
+	return ew.plaintextSegmentSize
+}
+
+// Write implements io.Writer.
+func (ew *encryptWriter) Write(p []byte) (int, error) {
+	if ew.closed {
+		return 0, errors.New("aes_gcm_hkdf_streaming: write to closed writer")
+	}
+	written := 0
+	for len(p) > 0 {
+		room := ew.capacity() - len(ew.buf)
+		n := room
+		if n > len(p) {
+			n = len(p)
+		}
+		ew.buf = append(ew.buf, p[:n]...)
+		p = p[n:]
+		written += n
+		if len(ew.buf) == ew.capacity() {
+			if err := ew.flushSegment(false); err != nil {
+				return written, err
+			}
+		}
+	}
+	return written, nil
+}
+
+func (ew *encryptWriter) flushSegment(last bool) error {
+	nonce := segmentNonce(ew.noncePrefix, ew.segmentNr, last)
+	ciphertext := ew.aead.Seal(nil, nonce, ew.buf, nil)
+	if _, err := ew.w.Write(ciphertext); err != nil {
+		return fmt.Errorf("aes_gcm_hkdf_streaming: failed to write segment: %s", err)
+	}
+	ew.segmentNr++
+	ew.buf = ew.buf[:0]
+	return nil
+}
+
+// Close implements io.Closer. It flushes the final segment, which may be
+// shorter than a full segment (or empty), and must be called exactly once.
+func (ew *encryptWriter) Close() error {
+	if ew.closed {
+		return nil
+	}
+	ew.closed = true
+	return ew.flushSegment(true)
+}
+
+// readSegment reads exactly size bytes from r, or as many as are available
+// before a clean EOF. eof is true only if zero bytes were available at all.
+func readSegment(r io.Reader, size int) (data []byte, eof bool, err error) {
+	buf := make([]byte, size)
+	n, rerr := io.ReadFull(r, buf)
+	switch rerr {
+	case nil:
+		return buf, false, nil
+	case io.ErrUnexpectedEOF:
+		return buf[:n], false, nil
+	case io.EOF:
+		return nil, true, nil
+	default:
+		return nil, false, rerr
+	}
+}
+
+// decryptReader reads ciphertext segments from r and decrypts them one at a
+// time. It keeps one segment of lookahead so that it can tell whether the
+// segment it is about to decrypt is the stream's final one, since that bit
+// is folded into the segment's nonce.
+type decryptReader struct {
+	r                     io.Reader
+	aead                  cipher.AEAD
+	noncePrefix           []byte
+	ciphertextSegmentSize int
+	firstSegmentOffset    int
+	segmentNr             uint32
+	plaintextBuf          []byte
+	pos                   int
+	pendingCiphertext     []byte
+	havePending           bool
+	done                  bool
+}
+
+func (dr *decryptReader) capacity(segmentNr uint32) int {
+	if segmentNr == 0 {
+		return dr.ciphertextSegmentSize - dr.firstSegmentOffset
+	}
+	return dr.ciphertextSegmentSize
+}
+
+// Read implements io.Reader.
+func (dr *decryptReader) Read(p []byte) (int, error) {
+	if dr.pos >= len(dr.plaintextBuf) {
+		if dr.done {
+			return 0, io.EOF
+		}
+		if err := dr.advance(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, dr.plaintextBuf[dr.pos:])
+	dr.pos += n
+	return n, nil
+}
+
+func (dr *decryptReader) advance() error {
+	var current []byte
+	if dr.havePending {
+		current = dr.pendingCiphertext
+		dr.havePending = false
+	} else {
+		data, eof, err := readSegment(dr.r, dr.capacity(dr.segmentNr))
+		if err != nil {
+			return err
+		}
+		if eof {
+			dr.done = true
+			return io.EOF
+		}
+		current = data
+	}
+
+	next, nextEOF, err := readSegment(dr.r, dr.capacity(dr.segmentNr+1))
+	if err != nil {
+		return err
+	}
+	last := nextEOF
+	if !nextEOF {
+		dr.pendingCiphertext = next
+		dr.havePending = true
+	}
+
+	nonce := segmentNonce(dr.noncePrefix, dr.segmentNr, last)
+	plaintext, err := dr.aead.Open(nil, nonce, current, nil)
+	if err != nil {
+		return fmt.Errorf("aes_gcm_hkdf_streaming: segment authentication failed: %s", err)
+	}
+	dr.plaintextBuf = plaintext
+	dr.pos = 0
+	dr.segmentNr++
+	if last {
+		dr.done = true
+	}
+	return nil
+}
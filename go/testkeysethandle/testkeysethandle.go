@@ -0,0 +1,31 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+// Package testkeysethandle provides a way for tests across the module to
+// build a *tink.KeysetHandle directly from a Keyset proto, bypassing the
+// validation that production code goes through when constructing a handle.
+// This lets tests assert that Primitives rejects deliberately-invalid
+// keysets (no primary, disabled primary, duplicate IDs, ...).
+package testkeysethandle
+
+import (
+	"github.com/google/tink/go/tink"
+	tinkpb "github.com/google/tink/proto/tink_go_proto"
+)
+
+// KeysetHandle creates a *tink.KeysetHandle wrapping ks, without validating
+// it. Only use this in tests.
+func KeysetHandle(ks *tinkpb.Keyset) (*tink.KeysetHandle, error) {
+	return tink.CreateKeysetHandleForTest(ks), nil
+}
@@ -0,0 +1,83 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package testutil
+
+import (
+	"errors"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/aead"
+	tinkpb "github.com/google/tink/proto/tink_go_proto"
+)
+
+// DummyAEAD is a dummy tink.AEAD used to check that a custom key manager
+// passed to tink.PrimitivesWithKeyManager is actually consulted instead of
+// the globally registered one. It does not perform any real encryption.
+type DummyAEAD struct {
+	Name string
+}
+
+// NewDummyAEAD creates a new DummyAEAD tagged with name.
+func NewDummyAEAD(name string) *DummyAEAD {
+	return &DummyAEAD{Name: name}
+}
+
+// Encrypt returns plaintext unchanged, tagged with d.Name.
+func (d *DummyAEAD) Encrypt(plaintext, associatedData []byte) ([]byte, error) {
+	return append([]byte(d.Name), plaintext...), nil
+}
+
+// Decrypt strips the d.Name tag added by Encrypt.
+func (d *DummyAEAD) Decrypt(ciphertext, associatedData []byte) ([]byte, error) {
+	if len(ciphertext) < len(d.Name) {
+		return nil, errors.New("dummy_aead: ciphertext too short")
+	}
+	return ciphertext[len(d.Name):], nil
+}
+
+// DummyAEADKeyManager is a KeyManager that claims the same type URL as
+// aead.AESGCMKeyManager but always produces a DummyAEAD, so tests can
+// confirm that RegisterKeyManager refuses to overwrite an existing
+// registration and that PrimitivesWithKeyManager lets callers override the
+// registry for a single Primitives call.
+type DummyAEADKeyManager struct{}
+
+// Primitive always returns a DummyAEAD, regardless of serializedKey.
+func (km *DummyAEADKeyManager) Primitive(serializedKey []byte) (interface{}, error) {
+	return NewDummyAEAD("dummy"), nil
+}
+
+// NewKey is not supported; DummyAEADKeyManager exists only to exercise the
+// Primitive path.
+func (km *DummyAEADKeyManager) NewKey(serializedKeyFormat []byte) (proto.Message, error) {
+	return nil, errors.New("dummy_aead_key_manager: not implemented")
+}
+
+// NewKeyData is not supported; DummyAEADKeyManager exists only to exercise
+// the Primitive path.
+func (km *DummyAEADKeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
+	return nil, errors.New("dummy_aead_key_manager: not implemented")
+}
+
+// DoesSupport returns true iff typeURL is aead.AESGCMTypeURL.
+func (km *DummyAEADKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == aead.AESGCMTypeURL
+}
+
+// TypeURL returns aead.AESGCMTypeURL, the same type URL as
+// aead.AESGCMKeyManager.
+func (km *DummyAEADKeyManager) TypeURL() string {
+	return aead.AESGCMTypeURL
+}
@@ -0,0 +1,53 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+// Package testutil provides fixtures shared by tests across the module.
+package testutil
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/subtle/random"
+	commonpb "github.com/google/tink/proto/common_go_proto"
+	hmacpb "github.com/google/tink/proto/hmac_go_proto"
+	tinkpb "github.com/google/tink/proto/tink_go_proto"
+)
+
+// NewHMACKey creates an HmacKey using the given hash type and tag size, with
+// a freshly generated key value. The key value is deliberately 20 bytes, a
+// size that isn't valid for any other registered AEAD key, so that tests
+// reinterpreting these bytes as a different key type reliably fail.
+func NewHMACKey(hashType commonpb.HashType, tagSize uint32) *hmacpb.HmacKey {
+	return &hmacpb.HmacKey{
+		Version: 0,
+		Params: &hmacpb.HmacParams{
+			Hash:    hashType,
+			TagSize: tagSize,
+		},
+		KeyValue: random.GetRandomBytes(20),
+	}
+}
+
+// NewHMACKeyData creates a KeyData wrapping a key created via NewHMACKey.
+func NewHMACKeyData(hashType commonpb.HashType, tagSize uint32) *tinkpb.KeyData {
+	key := NewHMACKey(hashType, tagSize)
+	serializedKey, err := proto.Marshal(key)
+	if err != nil {
+		panic("testutil: failed to marshal HmacKey")
+	}
+	return &tinkpb.KeyData{
+		TypeUrl:         "type.googleapis.com/google.crypto.tink.HmacKey",
+		Value:           serializedKey,
+		KeyMaterialType: tinkpb.KeyData_SYMMETRIC,
+	}
+}
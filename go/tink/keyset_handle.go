@@ -0,0 +1,46 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package tink
+
+import tinkpb "github.com/google/tink/proto/tink_go_proto"
+
+// KeysetHandle provides abstracted access to a Keyset, to limit the exposure
+// of actual protocol buffers that hold sensitive key material.
+type KeysetHandle struct {
+	ks *tinkpb.Keyset
+}
+
+// Keyset returns the Keyset proto wrapped by this handle. It is used by
+// FromKeysetHandle and Primitives, and lets tests outside the package
+// inspect the raw keyset; regular callers should go through Primitives or
+// PrimitivesWithKeyManager instead.
+func (kh *KeysetHandle) Keyset() *tinkpb.Keyset {
+	return kh.ks
+}
+
+// newKeysetHandle wraps ks into a KeysetHandle without performing any
+// validation. Used internally by KeysetManager, which validates the keyset
+// itself before calling this.
+func newKeysetHandle(ks *tinkpb.Keyset) *KeysetHandle {
+	return &KeysetHandle{ks: ks}
+}
+
+// CreateKeysetHandleForTest wraps ks into a KeysetHandle without performing
+// any validation. It exists solely so that the testkeysethandle package can
+// build handles around deliberately-invalid keysets; production code builds
+// handles via KeysetManager instead.
+func CreateKeysetHandleForTest(ks *tinkpb.Keyset) *KeysetHandle {
+	return newKeysetHandle(ks)
+}
@@ -0,0 +1,244 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package tink
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/subtle/random"
+	tinkpb "github.com/google/tink/proto/tink_go_proto"
+)
+
+// KeysetManager provides safe ways of mutating a keyset: adding and removing
+// keys, and moving the primary from one key to another, without ever
+// introducing a disabled primary or duplicate key IDs. A keyset with no
+// primary is possible (for example a new manager to which keys have only
+// been added); KeysetHandle rejects such a keyset. It is the Go analogue of
+// the KeysetManager found in the Java and C++ Tink implementations.
+type KeysetManager struct {
+	mu     sync.Mutex
+	keyset *tinkpb.Keyset
+}
+
+// New returns a KeysetManager wrapping an empty keyset.
+func New() *KeysetManager {
+	return &KeysetManager{keyset: &tinkpb.Keyset{}}
+}
+
+// FromKeysetHandle returns a KeysetManager wrapping a copy of handle's
+// keyset, so that further mutations don't alias the handle that was passed
+// in.
+func FromKeysetHandle(handle *KeysetHandle) (*KeysetManager, error) {
+	if handle == nil {
+		return nil, errors.New("keyset_manager: keyset handle can't be nil")
+	}
+	ks := handle.Keyset()
+	keys := make([]*tinkpb.Keyset_Key, len(ks.Key))
+	for i, k := range ks.Key {
+		keys[i] = proto.Clone(k).(*tinkpb.Keyset_Key)
+	}
+	return &KeysetManager{
+		keyset: &tinkpb.Keyset{
+			PrimaryKeyId: ks.PrimaryKeyId,
+			Key:          keys,
+		},
+	}, nil
+}
+
+// Add generates a new key from template, appends it to the keyset in
+// DISABLED state, and returns its key ID. Callers must still call Enable to
+// make it usable, and Enable before SetPrimary to make it the primary.
+func (km *KeysetManager) Add(template *tinkpb.KeyTemplate) (uint32, error) {
+	km.mu.Lock()
+	defer km.mu.Unlock()
+	return km.addLocked(template, tinkpb.KeyStatusType_DISABLED)
+}
+
+// Rotate generates a new key from template, appends it to the keyset, and
+// promotes it to primary in one step. It returns the new key's ID.
+func (km *KeysetManager) Rotate(template *tinkpb.KeyTemplate) (uint32, error) {
+	km.mu.Lock()
+	defer km.mu.Unlock()
+	keyID, err := km.addLocked(template, tinkpb.KeyStatusType_ENABLED)
+	if err != nil {
+		return 0, err
+	}
+	km.keyset.PrimaryKeyId = keyID
+	return keyID, nil
+}
+
+func (km *KeysetManager) addLocked(template *tinkpb.KeyTemplate, status tinkpb.KeyStatusType) (uint32, error) {
+	keyData, err := NewKeyData(template)
+	if err != nil {
+		return 0, fmt.Errorf("keyset_manager: cannot create KeyData: %s", err)
+	}
+	keyID := km.newKeyIDLocked()
+	km.keyset.Key = append(km.keyset.Key, &tinkpb.Keyset_Key{
+		KeyData:          keyData,
+		Status:           status,
+		KeyId:            keyID,
+		OutputPrefixType: tinkpb.OutputPrefixType_TINK,
+	})
+	return keyID, nil
+}
+
+// newKeyIDLocked returns a key ID that doesn't collide with any key already
+// in the keyset. km.mu must be held.
+func (km *KeysetManager) newKeyIDLocked() uint32 {
+	existing := make(map[uint32]bool, len(km.keyset.Key))
+	for _, k := range km.keyset.Key {
+		existing[k.KeyId] = true
+	}
+	for {
+		id := randomKeyID()
+		if !existing[id] {
+			return id
+		}
+	}
+}
+
+// SetPrimary sets the key with the given keyID as the keyset's primary key.
+// It is a no-op error -- the promotion is rejected -- if the key is disabled
+// or does not exist, since a disabled or missing key can never satisfy
+// Primitives' "exactly one enabled primary" invariant.
+func (km *KeysetManager) SetPrimary(keyID uint32) error {
+	km.mu.Lock()
+	defer km.mu.Unlock()
+	key, err := km.findKeyLocked(keyID)
+	if err != nil {
+		return err
+	}
+	if key.Status != tinkpb.KeyStatusType_ENABLED {
+		return fmt.Errorf("keyset_manager: key %d is not enabled, cannot become primary", keyID)
+	}
+	km.keyset.PrimaryKeyId = keyID
+	return nil
+}
+
+// Enable sets the status of the key with the given keyID to ENABLED.
+func (km *KeysetManager) Enable(keyID uint32) error {
+	km.mu.Lock()
+	defer km.mu.Unlock()
+	key, err := km.findKeyLocked(keyID)
+	if err != nil {
+		return err
+	}
+	key.Status = tinkpb.KeyStatusType_ENABLED
+	return nil
+}
+
+// Disable sets the status of the key with the given keyID to DISABLED. It
+// refuses to disable the current primary key, since that would leave the
+// keyset without an enabled primary.
+func (km *KeysetManager) Disable(keyID uint32) error {
+	km.mu.Lock()
+	defer km.mu.Unlock()
+	if keyID == km.keyset.PrimaryKeyId {
+		return fmt.Errorf("keyset_manager: cannot disable primary key %d", keyID)
+	}
+	key, err := km.findKeyLocked(keyID)
+	if err != nil {
+		return err
+	}
+	key.Status = tinkpb.KeyStatusType_DISABLED
+	return nil
+}
+
+// Delete removes the key with the given keyID from the keyset. It refuses
+// to delete the current primary key.
+func (km *KeysetManager) Delete(keyID uint32) error {
+	km.mu.Lock()
+	defer km.mu.Unlock()
+	if keyID == km.keyset.PrimaryKeyId {
+		return fmt.Errorf("keyset_manager: cannot delete primary key %d", keyID)
+	}
+	idx, err := km.indexOfLocked(keyID)
+	if err != nil {
+		return err
+	}
+	km.keyset.Key = append(km.keyset.Key[:idx], km.keyset.Key[idx+1:]...)
+	return nil
+}
+
+// Destroy removes the key material for the key with the given keyID,
+// leaving a tombstone behind (status DESTROYED) so that ciphertexts
+// produced under that key ID are recognizably unrecoverable rather than
+// silently unresolvable. It refuses to destroy the current primary key.
+func (km *KeysetManager) Destroy(keyID uint32) error {
+	km.mu.Lock()
+	defer km.mu.Unlock()
+	if keyID == km.keyset.PrimaryKeyId {
+		return fmt.Errorf("keyset_manager: cannot destroy primary key %d", keyID)
+	}
+	key, err := km.findKeyLocked(keyID)
+	if err != nil {
+		return err
+	}
+	key.KeyData = nil
+	key.Status = tinkpb.KeyStatusType_DESTROYED
+	return nil
+}
+
+// KeysetHandle returns a *KeysetHandle wrapping a copy of the managed
+// keyset, validating the invariants that Primitives relies on (a unique,
+// enabled primary key) before handing it out.
+func (km *KeysetManager) KeysetHandle() (*KeysetHandle, error) {
+	km.mu.Lock()
+	defer km.mu.Unlock()
+	if err := validateKeyset(km.keyset); err != nil {
+		return nil, fmt.Errorf("keyset_manager: invalid keyset: %s", err)
+	}
+	keys := make([]*tinkpb.Keyset_Key, len(km.keyset.Key))
+	for i, k := range km.keyset.Key {
+		keys[i] = proto.Clone(k).(*tinkpb.Keyset_Key)
+	}
+	ks := &tinkpb.Keyset{
+		PrimaryKeyId: km.keyset.PrimaryKeyId,
+		Key:          keys,
+	}
+	return newKeysetHandle(ks), nil
+}
+
+func (km *KeysetManager) findKeyLocked(keyID uint32) (*tinkpb.Keyset_Key, error) {
+	idx, err := km.indexOfLocked(keyID)
+	if err != nil {
+		return nil, err
+	}
+	return km.keyset.Key[idx], nil
+}
+
+func (km *KeysetManager) indexOfLocked(keyID uint32) (int, error) {
+	for i, k := range km.keyset.Key {
+		if k.KeyId == keyID {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("keyset_manager: no key with key id %d", keyID)
+}
+
+// randomKeyID returns a random, non-zero key ID. Key ID 0 is avoided only
+// as a matter of convention (it reads like an unset field); collisions
+// with existing keys are handled by the caller.
+func randomKeyID() uint32 {
+	for {
+		if id := binary.BigEndian.Uint32(random.GetRandomBytes(4)); id != 0 {
+			return id
+		}
+	}
+}
@@ -0,0 +1,275 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package tink_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/tink/go/aead"
+	"github.com/google/tink/go/tink"
+)
+
+func init() {
+	if err := aead.Register(); err != nil {
+		panic(fmt.Sprintf("cannot register AEAD key types: %v", err))
+	}
+}
+
+func TestKeysetManagerRotate(t *testing.T) {
+	km := tink.New()
+	keyID, err := km.Rotate(aead.AES128GCMKeyTemplate())
+	if err != nil {
+		t.Fatalf("Rotate() err = %v, want nil", err)
+	}
+	handle, err := km.KeysetHandle()
+	if err != nil {
+		t.Fatalf("KeysetHandle() err = %v, want nil", err)
+	}
+	if handle.Keyset().PrimaryKeyId != keyID {
+		t.Errorf("PrimaryKeyId = %d, want %d", handle.Keyset().PrimaryKeyId, keyID)
+	}
+	if _, err := tink.Primitives(handle); err != nil {
+		t.Errorf("Primitives() err = %v, want nil", err)
+	}
+}
+
+func TestKeysetManagerAddDoesNotChangePrimary(t *testing.T) {
+	km := tink.New()
+	firstID, err := km.Rotate(aead.AES128GCMKeyTemplate())
+	if err != nil {
+		t.Fatalf("Rotate() err = %v, want nil", err)
+	}
+	if _, err := km.Add(aead.AES256GCMKeyTemplate()); err != nil {
+		t.Fatalf("Add() err = %v, want nil", err)
+	}
+	handle, err := km.KeysetHandle()
+	if err != nil {
+		t.Fatalf("KeysetHandle() err = %v, want nil", err)
+	}
+	if handle.Keyset().PrimaryKeyId != firstID {
+		t.Errorf("PrimaryKeyId = %d, want unchanged %d", handle.Keyset().PrimaryKeyId, firstID)
+	}
+}
+
+func TestKeysetManagerNoPrimary(t *testing.T) {
+	km := tink.New()
+	if _, err := km.Add(aead.AES128GCMKeyTemplate()); err != nil {
+		t.Fatalf("Add() err = %v, want nil", err)
+	}
+	if _, err := km.KeysetHandle(); err == nil {
+		t.Errorf("KeysetHandle() succeeded with no primary key, want error")
+	}
+}
+
+func TestKeysetManagerSetPrimaryRejectsDisabledKey(t *testing.T) {
+	km := tink.New()
+	if _, err := km.Rotate(aead.AES128GCMKeyTemplate()); err != nil {
+		t.Fatalf("Rotate() err = %v, want nil", err)
+	}
+	disabledID, err := km.Add(aead.AES256GCMKeyTemplate())
+	if err != nil {
+		t.Fatalf("Add() err = %v, want nil", err)
+	}
+	if err := km.SetPrimary(disabledID); err == nil {
+		t.Errorf("SetPrimary() promoted a disabled key, want error")
+	}
+}
+
+func TestKeysetManagerSetPrimaryRejectsUnknownKey(t *testing.T) {
+	km := tink.New()
+	if _, err := km.Rotate(aead.AES128GCMKeyTemplate()); err != nil {
+		t.Fatalf("Rotate() err = %v, want nil", err)
+	}
+	if err := km.SetPrimary(0xdeadbeef); err == nil {
+		t.Errorf("SetPrimary() succeeded for unknown key id, want error")
+	}
+}
+
+func TestKeysetManagerEnableThenSetPrimary(t *testing.T) {
+	km := tink.New()
+	if _, err := km.Rotate(aead.AES128GCMKeyTemplate()); err != nil {
+		t.Fatalf("Rotate() err = %v, want nil", err)
+	}
+	secondID, err := km.Add(aead.AES256GCMKeyTemplate())
+	if err != nil {
+		t.Fatalf("Add() err = %v, want nil", err)
+	}
+	if err := km.Enable(secondID); err != nil {
+		t.Fatalf("Enable() err = %v, want nil", err)
+	}
+	if err := km.SetPrimary(secondID); err != nil {
+		t.Fatalf("SetPrimary() err = %v, want nil", err)
+	}
+	handle, err := km.KeysetHandle()
+	if err != nil {
+		t.Fatalf("KeysetHandle() err = %v, want nil", err)
+	}
+	if handle.Keyset().PrimaryKeyId != secondID {
+		t.Errorf("PrimaryKeyId = %d, want %d", handle.Keyset().PrimaryKeyId, secondID)
+	}
+}
+
+func TestKeysetManagerDisableRejectsPrimary(t *testing.T) {
+	km := tink.New()
+	primaryID, err := km.Rotate(aead.AES128GCMKeyTemplate())
+	if err != nil {
+		t.Fatalf("Rotate() err = %v, want nil", err)
+	}
+	if err := km.Disable(primaryID); err == nil {
+		t.Errorf("Disable() disabled the primary key, want error")
+	}
+}
+
+func TestKeysetManagerDeleteRejectsPrimary(t *testing.T) {
+	km := tink.New()
+	primaryID, err := km.Rotate(aead.AES128GCMKeyTemplate())
+	if err != nil {
+		t.Fatalf("Rotate() err = %v, want nil", err)
+	}
+	if err := km.Delete(primaryID); err == nil {
+		t.Errorf("Delete() deleted the primary key, want error")
+	}
+}
+
+func TestKeysetManagerDelete(t *testing.T) {
+	km := tink.New()
+	if _, err := km.Rotate(aead.AES128GCMKeyTemplate()); err != nil {
+		t.Fatalf("Rotate() err = %v, want nil", err)
+	}
+	secondID, err := km.Add(aead.AES256GCMKeyTemplate())
+	if err != nil {
+		t.Fatalf("Add() err = %v, want nil", err)
+	}
+	if err := km.Delete(secondID); err != nil {
+		t.Fatalf("Delete() err = %v, want nil", err)
+	}
+	handle, err := km.KeysetHandle()
+	if err != nil {
+		t.Fatalf("KeysetHandle() err = %v, want nil", err)
+	}
+	for _, k := range handle.Keyset().Key {
+		if k.KeyId == secondID {
+			t.Errorf("deleted key %d is still present in keyset", secondID)
+		}
+	}
+}
+
+func TestKeysetManagerDestroyRejectsPrimary(t *testing.T) {
+	km := tink.New()
+	primaryID, err := km.Rotate(aead.AES128GCMKeyTemplate())
+	if err != nil {
+		t.Fatalf("Rotate() err = %v, want nil", err)
+	}
+	if err := km.Destroy(primaryID); err == nil {
+		t.Errorf("Destroy() destroyed the primary key, want error")
+	}
+}
+
+func TestKeysetManagerDestroyClearsKeyMaterial(t *testing.T) {
+	km := tink.New()
+	if _, err := km.Rotate(aead.AES128GCMKeyTemplate()); err != nil {
+		t.Fatalf("Rotate() err = %v, want nil", err)
+	}
+	secondID, err := km.Add(aead.AES256GCMKeyTemplate())
+	if err != nil {
+		t.Fatalf("Add() err = %v, want nil", err)
+	}
+	if err := km.Destroy(secondID); err != nil {
+		t.Fatalf("Destroy() err = %v, want nil", err)
+	}
+	handle, err := km.KeysetHandle()
+	if err != nil {
+		t.Fatalf("KeysetHandle() err = %v, want nil", err)
+	}
+	for _, k := range handle.Keyset().Key {
+		if k.KeyId == secondID && k.KeyData != nil {
+			t.Errorf("destroyed key %d still carries key material", secondID)
+		}
+	}
+}
+
+func TestKeysetManagerFromKeysetHandleDoesNotAliasOriginal(t *testing.T) {
+	km := tink.New()
+	if _, err := km.Rotate(aead.AES128GCMKeyTemplate()); err != nil {
+		t.Fatalf("Rotate() err = %v, want nil", err)
+	}
+	handle, err := km.KeysetHandle()
+	if err != nil {
+		t.Fatalf("KeysetHandle() err = %v, want nil", err)
+	}
+
+	km2, err := tink.FromKeysetHandle(handle)
+	if err != nil {
+		t.Fatalf("FromKeysetHandle() err = %v, want nil", err)
+	}
+	if _, err := km2.Rotate(aead.AES256GCMKeyTemplate()); err != nil {
+		t.Fatalf("Rotate() err = %v, want nil", err)
+	}
+	if got, want := len(handle.Keyset().Key), 1; got != want {
+		t.Errorf("original handle mutated: len(Key) = %d, want %d", got, want)
+	}
+}
+
+func TestKeysetManagerFromKeysetHandleDoesNotAliasOriginalKeys(t *testing.T) {
+	km := tink.New()
+	if _, err := km.Rotate(aead.AES128GCMKeyTemplate()); err != nil {
+		t.Fatalf("Rotate() err = %v, want nil", err)
+	}
+	secondID, err := km.Add(aead.AES256GCMKeyTemplate())
+	if err != nil {
+		t.Fatalf("Add() err = %v, want nil", err)
+	}
+	handle, err := km.KeysetHandle()
+	if err != nil {
+		t.Fatalf("KeysetHandle() err = %v, want nil", err)
+	}
+
+	km2, err := tink.FromKeysetHandle(handle)
+	if err != nil {
+		t.Fatalf("FromKeysetHandle() err = %v, want nil", err)
+	}
+	if err := km2.Destroy(secondID); err != nil {
+		t.Fatalf("Destroy() err = %v, want nil", err)
+	}
+	for _, k := range handle.Keyset().Key {
+		if k.KeyId == secondID && k.KeyData == nil {
+			t.Errorf("destroying key %d via a keyset derived from handle also destroyed it on the original handle", secondID)
+		}
+	}
+}
+
+func TestKeysetHandleCallsDoNotAliasEachOther(t *testing.T) {
+	km := tink.New()
+	if _, err := km.Rotate(aead.AES128GCMKeyTemplate()); err != nil {
+		t.Fatalf("Rotate() err = %v, want nil", err)
+	}
+	secondID, err := km.Add(aead.AES256GCMKeyTemplate())
+	if err != nil {
+		t.Fatalf("Add() err = %v, want nil", err)
+	}
+	firstHandle, err := km.KeysetHandle()
+	if err != nil {
+		t.Fatalf("KeysetHandle() err = %v, want nil", err)
+	}
+	if err := km.Destroy(secondID); err != nil {
+		t.Fatalf("Destroy() err = %v, want nil", err)
+	}
+	for _, k := range firstHandle.Keyset().Key {
+		if k.KeyId == secondID && k.KeyData == nil {
+			t.Errorf("destroying key %d on the manager also destroyed it on a previously issued handle", secondID)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package tink
+
+import tinkpb "github.com/google/tink/proto/tink_go_proto"
+
+// CreateKeyset creates a Keyset proto with the given primary key ID and keys.
+func CreateKeyset(primaryKeyID uint32, keys []*tinkpb.Keyset_Key) *tinkpb.Keyset {
+	return &tinkpb.Keyset{
+		PrimaryKeyId: primaryKeyID,
+		Key:          keys,
+	}
+}
+
+// CreateKey creates a Keyset_Key proto from the given KeyData and metadata.
+func CreateKey(keyData *tinkpb.KeyData, status tinkpb.KeyStatusType, keyID uint32, prefixType tinkpb.OutputPrefixType) *tinkpb.Keyset_Key {
+	return &tinkpb.Keyset_Key{
+		KeyData:          keyData,
+		Status:           status,
+		KeyId:            keyID,
+		OutputPrefixType: prefixType,
+	}
+}
@@ -0,0 +1,28 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package tink
+
+// KmsClient knows how to produce primitives backed by keys that live in a
+// remote key management system, addressed by a URI such as
+// "aws-kms://arn:aws:kms:..." or "gcp-kms://projects/.../cryptoKeys/...".
+// Concrete clients are registered globally with RegisterKmsClient and looked
+// up by the URI prefix they claim via Supported.
+type KmsClient interface {
+	// Supported returns true if this client supports keyURI.
+	Supported(keyURI string) bool
+
+	// GetAEAD returns an AEAD backed by the remote key at keyURI.
+	GetAEAD(keyURI string) (AEAD, error)
+}
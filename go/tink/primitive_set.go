@@ -0,0 +1,130 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package tink
+
+import (
+	"errors"
+	"fmt"
+
+	tinkpb "github.com/google/tink/proto/tink_go_proto"
+)
+
+// Entry is a primitive instantiated from a single key of a keyset, together
+// with the key metadata needed to tell entries apart at runtime.
+type Entry struct {
+	KeyID     uint32
+	Primitive interface{}
+	Status    tinkpb.KeyStatusType
+	TypeURL   string
+}
+
+// PrimitiveSet holds the primitives instantiated from every enabled key in a
+// keyset, plus a pointer to the one instantiated from the keyset's primary
+// key.
+type PrimitiveSet struct {
+	Primary *Entry
+	Entries map[uint32][]*Entry
+}
+
+func newPrimitiveSet() *PrimitiveSet {
+	return &PrimitiveSet{
+		Entries: make(map[uint32][]*Entry),
+	}
+}
+
+func (ps *PrimitiveSet) addEntry(key *tinkpb.Keyset_Key, primitive interface{}) *Entry {
+	e := &Entry{
+		KeyID:     key.KeyId,
+		Primitive: primitive,
+		Status:    key.Status,
+		TypeURL:   key.KeyData.TypeUrl,
+	}
+	ps.Entries[key.KeyId] = append(ps.Entries[key.KeyId], e)
+	return e
+}
+
+// Primitives instantiates a primitive for every enabled key in handle's
+// keyset using the global registry, and returns the resulting PrimitiveSet.
+func Primitives(handle *KeysetHandle) (*PrimitiveSet, error) {
+	return primitives(handle, nil)
+}
+
+// PrimitivesWithKeyManager behaves like Primitives, except that for keys
+// whose type URL km supports, km is used to instantiate the primitive
+// instead of the global registry. This lets callers plug in a custom or test
+// key manager without registering it globally.
+func PrimitivesWithKeyManager(handle *KeysetHandle, km KeyManager) (*PrimitiveSet, error) {
+	return primitives(handle, km)
+}
+
+func primitives(handle *KeysetHandle, km KeyManager) (*PrimitiveSet, error) {
+	if handle == nil {
+		return nil, errors.New("primitive_set: keyset handle can't be nil")
+	}
+	keyset := handle.Keyset()
+	if err := validateKeyset(keyset); err != nil {
+		return nil, fmt.Errorf("primitive_set: invalid keyset: %s", err)
+	}
+	ps := newPrimitiveSet()
+	for _, key := range keyset.Key {
+		if key.Status != tinkpb.KeyStatusType_ENABLED {
+			continue
+		}
+		var p interface{}
+		var err error
+		if km != nil && km.DoesSupport(key.KeyData.TypeUrl) {
+			p, err = km.Primitive(key.KeyData.Value)
+		} else {
+			p, err = Primitive(key.KeyData.TypeUrl, key.KeyData.Value)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("primitive_set: cannot get primitive from key: %s", err)
+		}
+		entry := ps.addEntry(key, p)
+		if key.KeyId == keyset.PrimaryKeyId {
+			ps.Primary = entry
+		}
+	}
+	if ps.Primary == nil {
+		return nil, errors.New("primitive_set: no primary key")
+	}
+	return ps, nil
+}
+
+// validateKeyset checks the invariants that Primitives relies on: a
+// non-empty key list, no duplicate key IDs, and an enabled primary key.
+func validateKeyset(keyset *tinkpb.Keyset) error {
+	if keyset == nil || len(keyset.Key) == 0 {
+		return errors.New("keyset must not be empty")
+	}
+	seenIDs := make(map[uint32]bool)
+	sawPrimary := false
+	for _, key := range keyset.Key {
+		if seenIDs[key.KeyId] {
+			return fmt.Errorf("keyset contains duplicate key id: %d", key.KeyId)
+		}
+		seenIDs[key.KeyId] = true
+		if key.KeyId == keyset.PrimaryKeyId {
+			if key.Status != tinkpb.KeyStatusType_ENABLED {
+				return errors.New("primary key is not enabled")
+			}
+			sawPrimary = true
+		}
+	}
+	if !sawPrimary {
+		return errors.New("keyset has no primary key")
+	}
+	return nil
+}
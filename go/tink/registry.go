@@ -0,0 +1,129 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package tink
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	tinkpb "github.com/google/tink/proto/tink_go_proto"
+)
+
+var (
+	keyManagersMu sync.RWMutex
+	keyManagers   = make(map[string]KeyManager)
+
+	kmsClientsMu sync.RWMutex
+	kmsClients   []KmsClient
+)
+
+// RegisterKeyManager registers the given key manager under km.TypeURL(). It
+// is a no-op, not an error, if a key manager is already registered for that
+// type URL -- the first registration always wins so that test doubles
+// registered earlier in a binary's lifetime can't be clobbered by a later
+// Register() call.
+func RegisterKeyManager(km KeyManager) error {
+	keyManagersMu.Lock()
+	defer keyManagersMu.Unlock()
+	typeURL := km.TypeURL()
+	if _, existed := keyManagers[typeURL]; existed {
+		return nil
+	}
+	keyManagers[typeURL] = km
+	return nil
+}
+
+// GetKeyManager returns the key manager registered for typeURL.
+func GetKeyManager(typeURL string) (KeyManager, error) {
+	keyManagersMu.RLock()
+	defer keyManagersMu.RUnlock()
+	km, existed := keyManagers[typeURL]
+	if !existed {
+		return nil, fmt.Errorf("registry: unsupported key type: %s", typeURL)
+	}
+	return km, nil
+}
+
+// NewKeyData generates a new KeyData for the given key template using the
+// key manager registered for template.TypeUrl.
+func NewKeyData(template *tinkpb.KeyTemplate) (*tinkpb.KeyData, error) {
+	if template == nil {
+		return nil, errors.New("registry: invalid key template")
+	}
+	km, err := GetKeyManager(template.TypeUrl)
+	if err != nil {
+		return nil, err
+	}
+	return km.NewKeyData(template.Value)
+}
+
+// NewKey generates a new key for the given key template using the key
+// manager registered for template.TypeUrl.
+func NewKey(template *tinkpb.KeyTemplate) (proto.Message, error) {
+	if template == nil {
+		return nil, errors.New("registry: invalid key template")
+	}
+	km, err := GetKeyManager(template.TypeUrl)
+	if err != nil {
+		return nil, err
+	}
+	return km.NewKey(template.Value)
+}
+
+// PrimitiveFromKeyData creates a new primitive for the key in keyData using
+// the key manager registered for keyData.TypeUrl.
+func PrimitiveFromKeyData(keyData *tinkpb.KeyData) (interface{}, error) {
+	if keyData == nil {
+		return nil, errors.New("registry: invalid key data")
+	}
+	return Primitive(keyData.TypeUrl, keyData.Value)
+}
+
+// Primitive creates a new primitive for the given serialized key using the
+// key manager registered for typeURL.
+func Primitive(typeURL string, serializedKey []byte) (interface{}, error) {
+	if len(serializedKey) == 0 {
+		return nil, errors.New("registry: invalid serialized key")
+	}
+	km, err := GetKeyManager(typeURL)
+	if err != nil {
+		return nil, err
+	}
+	return km.Primitive(serializedKey)
+}
+
+// RegisterKmsClient registers client with the set of KMS clients that
+// KmsEnvelopeAead-style key managers consult to resolve a "kek_uri" to a
+// remote AEAD. Clients are tried in registration order; the first one whose
+// Supported reports true for a given key URI is used.
+func RegisterKmsClient(client KmsClient) {
+	kmsClientsMu.Lock()
+	defer kmsClientsMu.Unlock()
+	kmsClients = append(kmsClients, client)
+}
+
+// GetKmsClient returns the first registered KmsClient that supports keyURI.
+func GetKmsClient(keyURI string) (KmsClient, error) {
+	kmsClientsMu.RLock()
+	defer kmsClientsMu.RUnlock()
+	for _, client := range kmsClients {
+		if client.Supported(keyURI) {
+			return client, nil
+		}
+	}
+	return nil, fmt.Errorf("registry: no KMS client does support: %s", keyURI)
+}
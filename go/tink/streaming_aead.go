@@ -0,0 +1,38 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package tink
+
+import "io"
+
+// StreamingAEAD is the interface used for authenticated encryption with
+// associated data for data streams that may be too large to hold in memory
+// at once. Implementations encrypt and decrypt a stream segment by segment
+// rather than all at once, so memory use stays bounded regardless of the
+// size of the data read from or written to the wrapped io.Reader/io.Writer.
+type StreamingAEAD interface {
+	// NewEncryptingWriter returns a WriteCloser that encrypts everything
+	// written to it, and writes the ciphertext to w, using associatedData
+	// as additional authenticated data. associatedData must match the value
+	// passed to NewDecryptingReader when the resulting ciphertext is
+	// decrypted. The returned WriteCloser must be closed to flush the final
+	// segment.
+	NewEncryptingWriter(w io.Writer, associatedData []byte) (io.WriteCloser, error)
+
+	// NewDecryptingReader returns a Reader that reads and decrypts
+	// ciphertext from r, using associatedData as additional authenticated
+	// data. associatedData must match the value passed to
+	// NewEncryptingWriter when the ciphertext was produced.
+	NewDecryptingReader(r io.Reader, associatedData []byte) (io.Reader, error)
+}
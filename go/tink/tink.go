@@ -0,0 +1,80 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+// Package tink provides the primitive interfaces and the registry that the
+// rest of the module is built around: every concrete package (aead, mac, ...)
+// registers key managers here, and callers obtain primitives through those
+// interfaces rather than through package-specific types.
+package tink
+
+import (
+	"github.com/golang/protobuf/proto"
+	tinkpb "github.com/google/tink/proto/tink_go_proto"
+)
+
+// AEAD is the interface used for authenticated encryption with associated data.
+// Implementations of this interface are secure against adaptive chosen
+// ciphertext attacks. Encryption with associated data ensures authenticity
+// (who the sender is) and integrity (the data has not been tampered with) of
+// that data, but not its secrecy.
+type AEAD interface {
+	// Encrypt encrypts plaintext with associatedData as additional
+	// authenticated data. The resulting ciphertext allows for checking
+	// authenticity and integrity of associatedData, but does not guarantee
+	// its secrecy.
+	Encrypt(plaintext, associatedData []byte) ([]byte, error)
+
+	// Decrypt decrypts ciphertext with associatedData as additional
+	// authenticated data.
+	Decrypt(ciphertext, associatedData []byte) ([]byte, error)
+}
+
+// MAC is the interface used to authenticate data with a message authentication code.
+type MAC interface {
+	// ComputeMAC computes the message authentication code (MAC) for data.
+	ComputeMAC(data []byte) ([]byte, error)
+
+	// VerifyMAC verifies that mac is a correct authentication code for data.
+	VerifyMAC(mac, data []byte) error
+}
+
+// KeyManager knows how to generate keys of a specific type, and how to turn
+// serialized keys of that type into primitives. Every primitive package
+// implements one and registers it with RegisterKeyManager.
+type KeyManager interface {
+	// Primitive constructs a primitive instance for the key specified by
+	// serializedKey, which must be a serialized key protocol buffer
+	// understood by this key manager.
+	Primitive(serializedKey []byte) (interface{}, error)
+
+	// NewKey generates a new key according to the given serialized key
+	// format, which must be a serialized key format protocol buffer
+	// understood by this key manager.
+	NewKey(serializedKeyFormat []byte) (proto.Message, error)
+
+	// DoesSupport returns true iff this key manager supports key type
+	// identified by typeURL.
+	DoesSupport(typeURL string) bool
+
+	// TypeURL returns the type URL that identifies the key type of keys
+	// managed by this key manager.
+	TypeURL() string
+
+	// NewKeyData generates a new KeyData according to the given serialized
+	// key format, which must be a serialized key format protocol buffer
+	// understood by this key manager. It is called by the registry's
+	// NewKeyData, which passes the key format through unchanged; the key
+	// manager itself validates it.
+	NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error)
+}